@@ -0,0 +1,149 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// BenchResult holds the measurements of a benchmark
+type BenchResult struct {
+	NsPerOp     int64 `json:"ns_per_op"`     // nanoseconds per operation
+	BytesPerOp  int64 `json:"bytes_per_op"`  // allocated bytes per operation
+	AllocsPerOp int64 `json:"allocs_per_op"` // allocations per operation
+}
+
+// BenchBaseline records results of named benchmarks in a JSON file and,
+// on later runs, detects regressions with respect to the recorded values
+//
+//   Example:
+//     bl := check.NewBenchBaseline("testdata/bench.json", 20)
+//     bl.Run(tst, "ToJSON", func(b *testing.B) {
+//         for i := 0; i < b.N; i++ {
+//             res.ToJSON()
+//         }
+//     })
+//
+//   NOTE: baselines are recorded when missing or if UpdateMode() is true
+//
+type BenchBaseline struct {
+	Path       string  // JSON file with the baselines
+	MaxRegress float64 // maximum accepted regression in percent; e.g. 20 means up to 20% worse
+	WarnOnly   bool    // print warnings instead of reporting failures
+
+	mu        sync.Mutex             // protects baselines
+	baselines map[string]BenchResult // recorded results
+}
+
+// NewBenchBaseline returns a new BenchBaseline, loading the recorded results from path (if it exists)
+func NewBenchBaseline(path string, maxRegress float64) (o *BenchBaseline) {
+	o = new(BenchBaseline)
+	o.Path = path
+	o.MaxRegress = maxRegress
+	o.baselines = make(map[string]BenchResult)
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return
+	}
+	if err != nil {
+		Panic("cannot read benchmark baselines: %v", err)
+	}
+	if err = json.Unmarshal(b, &o.baselines); err != nil {
+		Panic("cannot parse benchmark baselines <%s>: %v", path, err)
+	}
+	return
+}
+
+// Run runs fn with testing.Benchmark (reporting allocations) and calls Check with the results
+func (o *BenchBaseline) Run(tst Reporter, name string, fn func(b *testing.B)) {
+	tst.Helper()
+	res := testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+		fn(b)
+	})
+	o.Check(tst, name, res)
+}
+
+// Check compares res with the baseline of name; or records res if there is no baseline
+func (o *BenchBaseline) Check(tst Reporter, name string, res testing.BenchmarkResult) {
+	tst.Helper()
+	cur := BenchResult{res.NsPerOp(), res.AllocedBytesPerOp(), res.AllocsPerOp()}
+
+	// record
+	o.mu.Lock()
+	base, ok := o.baselines[name]
+	if !ok || UpdateMode() {
+		o.baselines[name] = cur
+		err := o.save()
+		o.mu.Unlock()
+		if err != nil {
+			fail(tst, name, "cannot save benchmark baselines: %v\n", err)
+			return
+		}
+		passf(tst, name, "%s: baseline recorded: %d ns/op, %d B/op, %d allocs/op\n", name, cur.NsPerOp, cur.BytesPerOp, cur.AllocsPerOp)
+		return
+	}
+	o.mu.Unlock()
+
+	// compare
+	var problems []string
+	for _, m := range []struct {
+		unit      string
+		base, cur int64
+	}{
+		{"ns/op", base.NsPerOp, cur.NsPerOp},
+		{"B/op", base.BytesPerOp, cur.BytesPerOp},
+		{"allocs/op", base.AllocsPerOp, cur.AllocsPerOp},
+	} {
+		if p := regression(m.base, m.cur); p > o.MaxRegress {
+			problems = append(problems, fmt.Sprintf("%s: %d => %d (+%.1f%% > %g%%)", m.unit, m.base, m.cur, p, o.MaxRegress))
+		}
+	}
+	if len(problems) > 0 {
+		if o.WarnOnly {
+			fmt.Printf("WARNING: benchmark %s regressed:\n    %s\n", name, strings.Join(problems, "\n    "))
+			return
+		}
+		fail(tst, name, "benchmark %s regressed:\n    %s\n", name, strings.Join(problems, "\n    "))
+		return
+	}
+	passf(tst, name, "%s: OK (%d ns/op, %d B/op, %d allocs/op)\n", name, cur.NsPerOp, cur.BytesPerOp, cur.AllocsPerOp)
+}
+
+// Baseline returns the recorded result of name
+func (o *BenchBaseline) Baseline(name string) (res BenchResult, ok bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	res, ok = o.baselines[name]
+	return
+}
+
+// save writes the baselines to file
+func (o *BenchBaseline) save() error {
+	b, err := json.MarshalIndent(o.baselines, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(o.Path, append(b, '\n'), 0644)
+}
+
+// regression returns how much worse cur is than base, in percent
+//  NOTE: returns +Inf if base is zero and cur is positive
+func regression(base, cur int64) float64 {
+	if cur <= base {
+		return 0
+	}
+	if base == 0 {
+		return math.Inf(1)
+	}
+	return float64(cur-base) / float64(base) * 100
+}
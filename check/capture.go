@@ -0,0 +1,65 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"os"
+	"sync"
+)
+
+// captureMutex serializes calls to CaptureOutput
+var captureMutex sync.Mutex
+
+// CaptureOutput calls fn and returns what it wrote to os.Stdout, os.Stderr and the standard logger
+//  NOTE: (1) os.Stdout, os.Stderr and the output of the standard logger are restored when fn returns (or panics)
+//        (2) calls are serialized; however, output of other goroutines running meanwhile is captured as well
+func CaptureOutput(fn func()) (stdout, stderr, logs string) {
+	captureMutex.Lock()
+	defer captureMutex.Unlock()
+
+	// pipes
+	rOut, wOut, err := os.Pipe()
+	if err != nil {
+		Panic("cannot create pipe for stdout: %v", err)
+	}
+	rErr, wErr, err := os.Pipe()
+	if err != nil {
+		Panic("cannot create pipe for stderr: %v", err)
+	}
+	outC, errC := capturePipe(rOut), capturePipe(rErr)
+
+	// redirect
+	oldOut, oldErr, oldLog := os.Stdout, os.Stderr, log.Writer()
+	logBuf := new(bytes.Buffer)
+	os.Stdout, os.Stderr = wOut, wErr
+	log.SetOutput(logBuf)
+
+	// run and restore
+	func() {
+		defer func() {
+			os.Stdout, os.Stderr = oldOut, oldErr
+			log.SetOutput(oldLog)
+			wOut.Close()
+			wErr.Close()
+		}()
+		fn()
+	}()
+	return <-outC, <-errC, logBuf.String()
+}
+
+// capturePipe reads r until it is closed and then sends its content
+func capturePipe(r *os.File) <-chan string {
+	c := make(chan string, 1)
+	go func() {
+		buf := new(bytes.Buffer)
+		io.Copy(buf, r)
+		r.Close()
+		c <- buf.String()
+	}()
+	return c
+}
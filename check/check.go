@@ -5,96 +5,148 @@
 package check
 
 import (
-	"fmt"
 	"math"
-	"testing"
+	"strings"
 	"time"
 )
 
 // String checks string
-func String(tst *testing.T, msg, a, b string) {
+//  NOTE: a unified diff is reported if a or b has more than one line
+func String(tst Reporter, msg, a, b string) {
+	tst.Helper()
 	if a != b {
-		tst.Errorf("\n%q\nIS NOT EQUAL TO\n%q\n", a, b)
+		if strings.Contains(a, "\n") || strings.Contains(b, "\n") {
+			fail(tst, msg, "\n%s", Diff(a, b))
+			return
+		}
+		fail(tst, msg, "\n%q\nIS NOT EQUAL TO\n%q\n", a, b)
 		return
 	}
-	if verboseMode {
-		fmt.Printf("%s: OK\n", msg)
-	}
+	printOK(tst, msg)
 }
 
 // Int64 checks int64
-func Int64(tst *testing.T, msg string, a, b int64) {
+func Int64(tst Reporter, msg string, a, b int64) {
+	tst.Helper()
 	if a != b {
-		tst.Errorf("%v != %v\n", a, b)
+		fail(tst, msg, "%v != %v\n", a, b)
 		return
 	}
-	if verboseMode {
-		fmt.Printf("%s: OK\n", msg)
-	}
+	printOK(tst, msg)
 }
 
 // Int checks int
-func Int(tst *testing.T, msg string, a, b int) {
+func Int(tst Reporter, msg string, a, b int) {
+	tst.Helper()
 	if a != b {
-		tst.Errorf("%v != %v\n", a, b)
+		fail(tst, msg, "%v != %v\n", a, b)
 		return
 	}
-	if verboseMode {
-		fmt.Printf("%s: OK\n", msg)
-	}
+	printOK(tst, msg)
 }
 
 // Float64 checks float64
-func Float64(tst *testing.T, msg string, tol, a, b float64) {
-	if math.IsNaN(a) {
-		tst.Errorf("a=%v is NaN\n", a)
+func Float64(tst Reporter, msg string, tol, a, b float64) {
+	tst.Helper()
+	if stop := notFinite("", a, b); stop != "" {
+		fail(tst, msg, "%s", stop)
 		return
 	}
-	if math.IsInf(a, 0) {
-		tst.Errorf("a=%v is Inf\n", a)
+	if math.Abs(a-b) > tol {
+		fail(tst, msg, "%v != %v\n", a, b)
 		return
 	}
-	if math.IsNaN(b) {
-		tst.Errorf("b=%v is NaN\n", b)
+	printOK(tst, msg)
+}
+
+// Bools checks slice of bool
+func Bools(tst Reporter, msg string, a, b []bool) {
+	tst.Helper()
+	if len(a) != len(b) {
+		fail(tst, msg, "len(a)=%d != len(b)=%d\n", len(a), len(b))
 		return
 	}
-	if math.IsInf(b, 0) {
-		tst.Errorf("b=%v is Inf\n", b)
+	for i := 0; i < len(a); i++ {
+		if a[i] != b[i] {
+			fail(tst, msg, "a[%d]=%v != b[%d]=%v\n", i, a[i], i, b[i])
+			return
+		}
+	}
+	printOK(tst, msg)
+}
+
+// Strings checks slice of string
+func Strings(tst Reporter, msg string, a, b []string) {
+	tst.Helper()
+	if len(a) != len(b) {
+		fail(tst, msg, "len(a)=%d != len(b)=%d\n", len(a), len(b))
 		return
 	}
-	if math.Abs(a-b) > tol {
-		tst.Errorf("%v != %v\n", a, b)
+	for i := 0; i < len(a); i++ {
+		if a[i] != b[i] {
+			fail(tst, msg, "a[%d]=%q != b[%d]=%q\n", i, a[i], i, b[i])
+			return
+		}
+	}
+	printOK(tst, msg)
+}
+
+// Ints checks slice of int
+func Ints(tst Reporter, msg string, a, b []int) {
+	tst.Helper()
+	if len(a) != len(b) {
+		fail(tst, msg, "len(a)=%d != len(b)=%d\n", len(a), len(b))
 		return
 	}
-	if verboseMode {
-		fmt.Printf("%s: OK\n", msg)
+	for i := 0; i < len(a); i++ {
+		if a[i] != b[i] {
+			fail(tst, msg, "a[%d]=%v != b[%d]=%v\n", i, a[i], i, b[i])
+			return
+		}
 	}
+	printOK(tst, msg)
 }
 
-// Bools checks slice of bool
-func Bools(tst *testing.T, msg string, a, b []bool) {
+// Int64s checks slice of int64
+func Int64s(tst Reporter, msg string, a, b []int64) {
+	tst.Helper()
 	if len(a) != len(b) {
-		tst.Errorf("len(a)=%d != len(b)=%d\n", len(a), len(b))
+		fail(tst, msg, "len(a)=%d != len(b)=%d\n", len(a), len(b))
 		return
 	}
 	for i := 0; i < len(a); i++ {
 		if a[i] != b[i] {
-			tst.Errorf("a[%d]=%v != b[%d]=%v\n", i, a[i], i, b[i])
+			fail(tst, msg, "a[%d]=%v != b[%d]=%v\n", i, a[i], i, b[i])
 			return
 		}
 	}
-	if verboseMode {
-		fmt.Printf("%s: OK\n", msg)
+	printOK(tst, msg)
+}
+
+// ElementsMatch checks whether the slices (or arrays) a and b have the same elements in any order
+//  NOTE: elements are compared deeply and repeated elements must appear the same number of times;
+//        the failure message lists the missing (in a) and extra elements with their multiplicity;
+//        e.g. missing: "x" (×2)
+func ElementsMatch(tst Reporter, msg string, a, b interface{}) {
+	tst.Helper()
+	missing, extra, err := elementsDiff(a, b)
+	if err != nil {
+		fail(tst, msg, "%v\n", err)
+		return
+	}
+	if len(missing) > 0 || len(extra) > 0 {
+		fail(tst, msg, "%s\n", strings.Join(elementsReport(missing, extra), "\n"))
+		return
 	}
+	printOK(tst, msg)
 }
 
 // Time checks time.Time
-func Time(tst *testing.T, msg string, a, b time.Time) {
+func Time(tst Reporter, msg string, a, b time.Time) {
+	tst.Helper()
 	if a != b {
-		tst.Errorf("%v != %v\n", a, b)
+		fail(tst, msg, "%v != %v\n", a, b)
 		return
 	}
-	if verboseMode {
-		fmt.Printf("%s: OK\n", msg)
-	}
+	printOK(tst, msg)
 }
@@ -0,0 +1,244 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock defines the functions of package time that depend on the current time.
+// Code accepting a Clock can be tested deterministically with FakeClock
+type Clock interface {
+	Now() time.Time                         // returns the current time
+	Since(t time.Time) time.Duration        // returns the time elapsed since t
+	Sleep(d time.Duration)                  // pauses for d
+	After(d time.Duration) <-chan time.Time // returns a channel receiving the time after d
+	NewTimer(d time.Duration) Timer         // returns a timer firing after d
+	NewTicker(d time.Duration) Ticker       // returns a ticker firing every d
+}
+
+// Timer defines a single event; see time.Timer
+type Timer interface {
+	C() <-chan time.Time        // returns the channel receiving the time when the timer fires
+	Stop() bool                 // stops the timer; returns false if it had already fired or been stopped
+	Reset(d time.Duration) bool // restarts the timer; returns true if it was active
+}
+
+// Ticker defines periodic events; see time.Ticker
+type Ticker interface {
+	C() <-chan time.Time // returns the channel receiving the ticks
+	Stop()               // stops the ticker
+}
+
+// RealClock implements Clock with package time
+var RealClock Clock = realClock{}
+
+// realClock implements Clock with package time
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration        { return time.Since(t) }
+func (realClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) NewTimer(d time.Duration) Timer         { return realTimer{time.NewTimer(d)} }
+func (realClock) NewTicker(d time.Duration) Ticker       { return realTicker{time.NewTicker(d)} }
+
+// realTimer implements Timer with time.Timer
+type realTimer struct{ t *time.Timer }
+
+func (o realTimer) C() <-chan time.Time        { return o.t.C }
+func (o realTimer) Stop() bool                 { return o.t.Stop() }
+func (o realTimer) Reset(d time.Duration) bool { return o.t.Reset(d) }
+
+// realTicker implements Ticker with time.Ticker
+type realTicker struct{ t *time.Ticker }
+
+func (o realTicker) C() <-chan time.Time { return o.t.C }
+func (o realTicker) Stop()               { o.t.Stop() }
+
+// FakeClock implements Clock with a time that only changes when Advance, Set or Sleep are called
+//
+//   Example:
+//     clock := check.NewFakeClock(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+//     timer := clock.NewTimer(time.Minute)
+//     clock.Advance(time.Minute) // timer fires now
+//
+//  NOTE: Sleep advances the clock instead of blocking; thus, retry loops calling Sleep
+//        (e.g. WaitForClock) run instantly
+type FakeClock struct {
+	mu     sync.Mutex   // protects the fields below
+	now    time.Time    // current time
+	timers []*fakeTimer // active timers and tickers
+}
+
+// NewFakeClock returns a new FakeClock set to start
+func NewFakeClock(start time.Time) (o *FakeClock) {
+	o = new(FakeClock)
+	o.now = start
+	return
+}
+
+// Now returns the current time of the fake clock
+func (o *FakeClock) Now() time.Time {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.now
+}
+
+// Since returns the time elapsed since t according to the fake clock
+func (o *FakeClock) Since(t time.Time) time.Duration {
+	return o.Now().Sub(t)
+}
+
+// Sleep advances the fake clock by d
+func (o *FakeClock) Sleep(d time.Duration) {
+	o.Advance(d)
+}
+
+// After returns a channel receiving the time when the fake clock is advanced by d
+func (o *FakeClock) After(d time.Duration) <-chan time.Time {
+	return o.NewTimer(d).C()
+}
+
+// NewTimer returns a timer firing when the fake clock is advanced by d
+func (o *FakeClock) NewTimer(d time.Duration) Timer {
+	return o.newTimer(d, 0)
+}
+
+// NewTicker returns a ticker firing each time the fake clock is advanced by d
+//  NOTE: as with time.Ticker, ticks are dropped if the receiver is not ready
+func (o *FakeClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		Panic("non-positive interval for NewTicker: %v\n", d)
+	}
+	return fakeTicker{o.newTimer(d, d)}
+}
+
+// Advance moves the fake clock forward by d and fires the timers that are due
+func (o *FakeClock) Advance(d time.Duration) {
+	o.Set(o.Now().Add(d))
+}
+
+// Set moves the fake clock to t and fires the timers that are due, in chronological order
+//  NOTE: the clock never goes backwards; if t is before the current time, nothing happens
+func (o *FakeClock) Set(t time.Time) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for {
+		sort.SliceStable(o.timers, func(i, j int) bool { return o.timers[i].when.Before(o.timers[j].when) })
+		if len(o.timers) == 0 || o.timers[0].when.After(t) {
+			break
+		}
+		timer := o.timers[0]
+		if timer.when.After(o.now) {
+			o.now = timer.when
+		}
+		timer.fire(o.now)
+		if timer.period > 0 {
+			timer.when = timer.when.Add(timer.period)
+		} else {
+			o.timers = o.timers[1:]
+		}
+	}
+	if t.After(o.now) {
+		o.now = t
+	}
+}
+
+// Pending returns the number of active timers and tickers
+//  NOTE: useful to wait until the code under test has created its timers
+func (o *FakeClock) Pending() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.timers)
+}
+
+// newTimer creates and registers a timer; period > 0 makes a ticker
+func (o *FakeClock) newTimer(d, period time.Duration) (t *fakeTimer) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	t = &fakeTimer{clock: o, c: make(chan time.Time, 1), when: o.now.Add(d), period: period}
+	if d <= 0 {
+		t.fire(o.now)
+		if period == 0 {
+			return
+		}
+	}
+	o.timers = append(o.timers, t)
+	return
+}
+
+// remove unregisters a timer and returns whether it was active; o.mu must be locked
+func (o *FakeClock) remove(t *fakeTimer) bool {
+	for i, timer := range o.timers {
+		if timer == t {
+			o.timers = append(o.timers[:i], o.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// fakeTimer implements Timer for FakeClock; it also holds the data of tickers
+type fakeTimer struct {
+	clock  *FakeClock     // clock holding this timer
+	c      chan time.Time // channel receiving the time when firing
+	when   time.Time      // next firing time
+	period time.Duration  // interval between ticks; 0 for timers
+}
+
+func (o *fakeTimer) C() <-chan time.Time { return o.c }
+
+// Stop unregisters the timer
+func (o *fakeTimer) Stop() bool {
+	o.clock.mu.Lock()
+	defer o.clock.mu.Unlock()
+	return o.clock.remove(o)
+}
+
+// Reset sets the timer to fire when the fake clock is advanced by d
+func (o *fakeTimer) Reset(d time.Duration) (active bool) {
+	o.clock.mu.Lock()
+	defer o.clock.mu.Unlock()
+	active = o.clock.remove(o)
+	o.when = o.clock.now.Add(d)
+	if d <= 0 && o.period == 0 {
+		o.fire(o.clock.now)
+		return
+	}
+	o.clock.timers = append(o.clock.timers, o)
+	return
+}
+
+// fakeTicker implements Ticker for FakeClock
+type fakeTicker struct{ *fakeTimer }
+
+// Stop unregisters the ticker
+func (o fakeTicker) Stop() { o.fakeTimer.Stop() }
+
+// fire sends now to the channel unless it is full
+func (o *fakeTimer) fire(now time.Time) {
+	select {
+	case o.c <- now:
+	default:
+	}
+}
+
+// TimeNear checks whether a and b differ by no more than tolerance
+//  NOTE: the times are compared directly because a.Sub(b) saturates for differences above ~292 years
+func TimeNear(tst Reporter, msg string, a, b time.Time, tolerance time.Duration) {
+	tst.Helper()
+	if a.Before(b.Add(-tolerance)) || a.After(b.Add(tolerance)) {
+		diff := a.Sub(b)
+		if diff < 0 {
+			diff = -diff
+		}
+		fail(tst, msg, "%v != %v\ndifference %v > tolerance %v\n", a, b, diff, tolerance)
+		return
+	}
+	printOK(tst, msg)
+}
@@ -0,0 +1,154 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// CmdOptions holds options for running commands with CmdWith
+type CmdOptions struct {
+	Timeout time.Duration // maximum running time; the process group is killed afterwards; 0 means no limit
+	Env     []string      // "KEY=value" pairs overriding the environment of this process
+	Stdin   string        // standard input
+	Dir     string        // working directory; "" means the current directory
+}
+
+// DefaultCmdOptions returns the default options for Cmd: one minute timeout
+func DefaultCmdOptions() CmdOptions {
+	return CmdOptions{Timeout: time.Minute}
+}
+
+// CmdResult holds the result of a command run by Cmd
+type CmdResult struct {
+	Line     string        // command line; e.g. "ls -la"
+	Stdout   string        // standard output
+	Stderr   string        // standard error
+	ExitCode int           // exit code; -1 if the command could not be started or was killed
+	Duration time.Duration // running time
+	TimedOut bool          // the command was killed because of the timeout
+
+	tst Reporter // test used by the assertion methods
+}
+
+// Cmd runs a command using the default options and returns its result
+//
+//   Example:
+//     check.Cmd(tst, "go", "run", "./cmd/hello", "-name", "x").
+//         Success().
+//         OutIs("hello x\n").
+//         ErrThat(check.Empty())
+//
+//  NOTE: the test fails if the command cannot be started or times out; a non-zero
+//        exit code is only a failure if checked (e.g. by Success)
+func Cmd(tst Reporter, name string, args ...string) *CmdResult {
+	tst.Helper()
+	return CmdWith(tst, DefaultCmdOptions(), name, args...)
+}
+
+// CmdWith runs a command and returns its result
+//  NOTE: the command runs in a new process group, which is killed if the timeout is reached;
+//        thus, subprocesses started by the command are killed as well (except on platforms without
+//        process groups, such as Windows, where only the command is killed)
+func CmdWith(tst Reporter, opts CmdOptions, name string, args ...string) (o *CmdResult) {
+	tst.Helper()
+	o = &CmdResult{Line: strings.Join(append([]string{name}, args...), " "), ExitCode: -1, tst: tst}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(opts.Stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Dir = opts.Dir
+	cmd.Env = append(os.Environ(), opts.Env...)
+	cmdSetGroup(cmd)
+
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		fail(tst, o.Line, "cannot start command: %v\n", err)
+		return
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	var timeout <-chan time.Time
+	if opts.Timeout > 0 {
+		timer := time.NewTimer(opts.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	var err error
+	select {
+	case err = <-done:
+	case <-timeout:
+		o.TimedOut = true
+		if e := cmdKillGroup(cmd); e != nil {
+			fail(tst, o.Line, "cannot kill command after timeout of %v: %v\n", opts.Timeout, e)
+		}
+		err = <-done
+	}
+	o.Duration = time.Since(start)
+	o.Stdout, o.Stderr = stdout.String(), stderr.String()
+	if cmd.ProcessState != nil {
+		o.ExitCode = cmd.ProcessState.ExitCode()
+	}
+	if o.TimedOut {
+		fail(tst, o.Line, "command killed after timeout of %v\nstdout:\n%s\nstderr:\n%s\n", opts.Timeout, o.Stdout, o.Stderr)
+		return
+	}
+	if _, exit := err.(*exec.ExitError); err != nil && !exit {
+		fail(tst, o.Line, "command failed: %v\n", err)
+		return
+	}
+	passf(tst, o.Line, "$ %s: OK (exit code %d; %v)\n", o.Line, o.ExitCode, o.Duration.Round(time.Millisecond))
+	return
+}
+
+// Exit checks the exit code; on failure, stderr is also shown
+func (o *CmdResult) Exit(code int) *CmdResult {
+	o.tst.Helper()
+	if o.ExitCode != code {
+		fail(o.tst, o.Line+": exit code", "exit code %d != %d\nstderr:\n%s\n", o.ExitCode, code, o.Stderr)
+		return o
+	}
+	printOK(o.tst, o.Line+": exit code")
+	return o
+}
+
+// Success checks whether the exit code is zero
+func (o *CmdResult) Success() *CmdResult {
+	o.tst.Helper()
+	return o.Exit(0)
+}
+
+// OutIs checks the standard output; see String
+func (o *CmdResult) OutIs(want string) *CmdResult {
+	o.tst.Helper()
+	String(o.tst, o.Line+": stdout", o.Stdout, want)
+	return o
+}
+
+// ErrIs checks the standard error; see String
+func (o *CmdResult) ErrIs(want string) *CmdResult {
+	o.tst.Helper()
+	String(o.tst, o.Line+": stderr", o.Stderr, want)
+	return o
+}
+
+// OutThat checks the standard output with a matcher; see That
+func (o *CmdResult) OutThat(m Matcher) *CmdResult {
+	o.tst.Helper()
+	That(o.tst, o.Line+": stdout", o.Stdout, m)
+	return o
+}
+
+// ErrThat checks the standard error with a matcher; see That
+func (o *CmdResult) ErrThat(m Matcher) *CmdResult {
+	o.tst.Helper()
+	That(o.tst, o.Line+": stderr", o.Stderr, m)
+	return o
+}
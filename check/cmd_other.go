@@ -0,0 +1,18 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package check
+
+import "os/exec"
+
+// cmdSetGroup does nothing on platforms without process groups (e.g. Windows)
+func cmdSetGroup(cmd *exec.Cmd) {}
+
+// cmdKillGroup kills the command only; subprocesses started by it are not killed
+func cmdKillGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
@@ -0,0 +1,23 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package check
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// cmdSetGroup makes the command run in a new process group; subprocesses get the same process group ID
+func cmdSetGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// cmdKillGroup kills the process group of a command started with cmdSetGroup
+func cmdKillGroup(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
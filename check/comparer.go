@@ -0,0 +1,176 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// DeepOption modifies how Deep and DeepDiff compare values
+type DeepOption func(o *deepWalker)
+
+// registry holds the comparers registered by RegisterComparer
+var registry struct {
+	mu        sync.RWMutex                   // protects the field below
+	comparers map[reflect.Type]reflect.Value // comparers indexed by type
+}
+
+// RegisterComparer registers a function deciding whether two values of a type are equal;
+// it is used by Deep and DeepDiff whenever values of that type are found, at any depth
+//
+//   Input:
+//     fn -- func(a, b T) bool; returns true if a and b are equal
+//
+//   Output:
+//     unregister -- removes the comparer (e.g. defer check.RegisterComparer(fn)())
+//
+//   Example:
+//     check.RegisterComparer(func(a, b time.Time) bool { return a.Equal(b) })
+//
+//  NOTE: (1) comparers given to Deep with the Comparer option take precedence
+//        (2) comparers are not applied to unexported fields, which cannot be passed to fn
+//        (3) panics if fn does not have the required signature
+func RegisterComparer(fn interface{}) (unregister func()) {
+	t, f := comparerFunc(fn)
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if registry.comparers == nil {
+		registry.comparers = make(map[reflect.Type]reflect.Value)
+	}
+	registry.comparers[t] = f
+	return func() {
+		registry.mu.Lock()
+		defer registry.mu.Unlock()
+		if registry.comparers[t] == f {
+			delete(registry.comparers, t)
+		}
+	}
+}
+
+// Comparer uses fn, a func(a, b T) bool, to compare values of type T; see RegisterComparer
+func Comparer(fn interface{}) DeepOption {
+	t, f := comparerFunc(fn)
+	return func(o *deepWalker) {
+		if o.comparers == nil {
+			o.comparers = make(map[reflect.Type]reflect.Value)
+		}
+		o.comparers[t] = f
+	}
+}
+
+// IgnoreFields ignores the named fields of the struct type of sample
+//   Example: check.IgnoreFields(User{}, "CreatedAt", "ID")
+//  NOTE: (1) sample may also be a pointer to the struct; panics if a field does not exist
+//        (2) promoted fields are ignored in their embedded struct, wherever that struct is found
+func IgnoreFields(sample interface{}, names ...string) DeepOption {
+	t := reflect.TypeOf(sample)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		Panic("IgnoreFields requires a struct; got %T\n", sample)
+	}
+	owners := make([]reflect.Type, len(names)) // structs declaring the fields
+	for i, name := range names {
+		f, ok := t.FieldByName(name)
+		if !ok {
+			Panic("struct %v has no field %q\n", t, name)
+		}
+		owners[i] = t
+		for _, k := range f.Index[:len(f.Index)-1] { // promoted fields are declared by embedded structs
+			owners[i] = owners[i].Field(k).Type
+			if owners[i].Kind() == reflect.Ptr {
+				owners[i] = owners[i].Elem()
+			}
+		}
+	}
+	return func(o *deepWalker) {
+		if o.ignored == nil {
+			o.ignored = make(map[reflect.Type]map[string]bool)
+		}
+		for i, name := range names {
+			if o.ignored[owners[i]] == nil {
+				o.ignored[owners[i]] = make(map[string]bool)
+			}
+			o.ignored[owners[i]][name] = true
+		}
+	}
+}
+
+// IgnoreUnexported ignores the unexported fields of all structs
+func IgnoreUnexported() DeepOption {
+	return func(o *deepWalker) {
+		o.ignoreUnexported = true
+	}
+}
+
+// EquateApprox compares float32 and float64 numbers (including those in complex numbers) with tolerance tol
+//   Example: check.EquateApprox(check.AbsTol(1e-15))
+//  NOTE: finite numbers are compared as in check.Float64; however, unlike check.Float64, equal
+//        infinities are considered equal (as in Deep without options) and NaN never equals anything
+func EquateApprox(tol Tolerance) DeepOption {
+	return func(o *deepWalker) {
+		o.tol = &tol
+	}
+}
+
+// EquateEmpty considers nil and empty slices (or maps) as equal
+func EquateEmpty() DeepOption {
+	return func(o *deepWalker) {
+		o.equateEmpty = true
+	}
+}
+
+// SortSlices sorts (copies of) slices of T before comparing them, with less being a func(a, b T) bool
+//  NOTE: the indices in the differences refer to the sorted slices; panics if less has the wrong signature
+func SortSlices(less interface{}) DeepOption {
+	t, f := comparerFunc(less)
+	return func(o *deepWalker) {
+		if o.sorters == nil {
+			o.sorters = make(map[reflect.Type]reflect.Value)
+		}
+		o.sorters[t] = f
+	}
+}
+
+// comparerFunc checks that fn is a func(a, b T) bool and returns T and fn
+func comparerFunc(fn interface{}) (reflect.Type, reflect.Value) {
+	f := reflect.ValueOf(fn)
+	if f.Kind() == reflect.Func && !f.IsNil() {
+		t := f.Type()
+		if t.NumIn() == 2 && t.In(0) == t.In(1) && t.NumOut() == 1 && t.Out(0).Kind() == reflect.Bool {
+			return t.In(0), f
+		}
+	}
+	Panic("function must be a func(a, b T) bool; got %T\n", fn)
+	return nil, f
+}
+
+// comparer returns the comparer for values of type t, if any
+func (o *deepWalker) comparer(t reflect.Type) (f reflect.Value, ok bool) {
+	if f, ok = o.comparers[t]; ok {
+		return
+	}
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	f, ok = registry.comparers[t]
+	return
+}
+
+// sorted returns a sorted copy of slice s if a sorting function was given for its elements
+func (o *deepWalker) sorted(s reflect.Value) reflect.Value {
+	less, ok := o.sorters[s.Type().Elem()]
+	if !ok || s.Len() < 2 || !s.Index(0).CanInterface() {
+		return s
+	}
+	c := reflect.MakeSlice(s.Type(), s.Len(), s.Len())
+	reflect.Copy(c, s)
+	sort.SliceStable(c.Interface(), func(i, j int) bool {
+		return less.Call([]reflect.Value{c.Index(i), c.Index(j)})[0].Bool()
+	})
+	return c
+}
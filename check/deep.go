@@ -0,0 +1,303 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Deep checks whether a and b are deeply equal
+//  NOTE: (1) walks structs, maps, slices, arrays, pointers and interfaces recursively
+//            and reports every differing path; e.g. .Users[3].Email: "a" != "b"
+//        (2) opts (e.g. IgnoreFields, EquateApprox) and registered comparers (see RegisterComparer)
+//            change how values are compared
+func Deep(tst Reporter, msg string, a, b interface{}, opts ...DeepOption) {
+	tst.Helper()
+	diffs := DeepDiff(a, b, opts...)
+	if len(diffs) > 0 {
+		fail(tst, msg, "%s\n", strings.Join(diffs, "\n"))
+		return
+	}
+	printOK(tst, msg)
+}
+
+// DeepDiff returns the list of differences between a and b; one entry per path
+//  NOTE: returns nil if a and b are deeply equal
+func DeepDiff(a, b interface{}, opts ...DeepOption) (diffs []string) {
+	w := &deepWalker{visited: make(map[deepVisit]bool)}
+	for _, opt := range opts {
+		opt(w)
+	}
+	w.walk("", reflect.ValueOf(a), reflect.ValueOf(b))
+	return w.diffs
+}
+
+// deepVisit holds a pair of references already compared (avoids infinite cycles)
+//  NOTE: the lengths distinguish slices sharing the same backing array
+type deepVisit struct {
+	a, b   uintptr
+	la, lb int
+	typ    reflect.Type
+}
+
+// deepWalker walks two values recursively collecting differences
+type deepWalker struct {
+	diffs   []string
+	visited map[deepVisit]bool
+
+	// options
+	comparers        map[reflect.Type]reflect.Value   // comparers given by the Comparer option
+	sorters          map[reflect.Type]reflect.Value   // less functions of elements given by SortSlices
+	ignored          map[reflect.Type]map[string]bool // fields ignored by IgnoreFields
+	ignoreUnexported bool                             // unexported fields are ignored
+	equateEmpty      bool                             // nil and empty slices (or maps) are equal
+	tol              *Tolerance                       // tolerance given by EquateApprox [may be nil]
+}
+
+// report records a difference at path
+func (o *deepWalker) report(path, format string, prm ...interface{}) {
+	text := fmt.Sprintf(format, prm...)
+	if path != "" {
+		text = path + ": " + text
+	}
+	o.diffs = append(o.diffs, text)
+}
+
+// seen returns true if the pair of references has already been visited
+func (o *deepWalker) seen(a, b reflect.Value) bool {
+	v := deepVisit{a: a.Pointer(), b: b.Pointer(), typ: a.Type()}
+	if a.Kind() == reflect.Slice {
+		v.la, v.lb = a.Len(), b.Len()
+	}
+	if o.visited[v] {
+		return true
+	}
+	o.visited[v] = true
+	return false
+}
+
+// walk compares a and b at path
+func (o *deepWalker) walk(path string, a, b reflect.Value) {
+
+	// nil interfaces
+	if !a.IsValid() || !b.IsValid() {
+		if a.IsValid() != b.IsValid() {
+			o.report(path, "%s != %s", deepFormat(a), deepFormat(b))
+		}
+		return
+	}
+
+	// types
+	if a.Type() != b.Type() {
+		o.report(path, "type %v != type %v", a.Type(), b.Type())
+		return
+	}
+
+	// custom comparers
+	if f, ok := o.comparer(a.Type()); ok && a.CanInterface() && b.CanInterface() {
+		if !f.Call([]reflect.Value{a, b})[0].Bool() {
+			o.report(path, "%s != %s", deepFormat(a), deepFormat(b))
+		}
+		return
+	}
+
+	switch a.Kind() {
+
+	case reflect.Bool:
+		if a.Bool() != b.Bool() {
+			o.report(path, "%s != %s", deepFormat(a), deepFormat(b))
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if a.Int() != b.Int() {
+			o.report(path, "%s != %s", deepFormat(a), deepFormat(b))
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if a.Uint() != b.Uint() {
+			o.report(path, "%s != %s", deepFormat(a), deepFormat(b))
+		}
+
+	case reflect.Float32, reflect.Float64:
+		if !o.equalFloats(a.Float(), b.Float()) {
+			o.reportFloats(path, a, b, a.Float(), b.Float())
+		}
+
+	case reflect.Complex64, reflect.Complex128:
+		ca, cb := a.Complex(), b.Complex()
+		if !o.equalFloats(real(ca), real(cb)) || !o.equalFloats(imag(ca), imag(cb)) {
+			o.report(path, "%s != %s", deepFormat(a), deepFormat(b))
+		}
+
+	case reflect.String:
+		if a.String() != b.String() {
+			o.report(path, "%s != %s", deepFormat(a), deepFormat(b))
+		}
+
+	case reflect.Chan, reflect.UnsafePointer:
+		if a.Pointer() != b.Pointer() {
+			o.report(path, "%s != %s", deepFormat(a), deepFormat(b))
+		}
+
+	case reflect.Func:
+		if !a.IsNil() || !b.IsNil() {
+			o.report(path, "functions can only be compared when both are nil")
+		}
+
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				o.report(path, "%s != %s", deepFormat(a), deepFormat(b))
+			}
+			return
+		}
+		o.walk(path, a.Elem(), b.Elem())
+
+	case reflect.Ptr:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				o.report(path, "%s != %s", deepFormat(a), deepFormat(b))
+			}
+			return
+		}
+		if a.Pointer() == b.Pointer() || o.seen(a, b) {
+			return
+		}
+		o.walk(path, a.Elem(), b.Elem())
+
+	case reflect.Struct:
+		t := a.Type()
+		for i := 0; i < a.NumField(); i++ {
+			f := t.Field(i)
+			if (o.ignoreUnexported && f.PkgPath != "") || o.ignored[t][f.Name] {
+				continue
+			}
+			o.walk(path+"."+f.Name, a.Field(i), b.Field(i))
+		}
+
+	case reflect.Array:
+		for i := 0; i < a.Len(); i++ {
+			o.walk(fmt.Sprintf("%s[%d]", path, i), a.Index(i), b.Index(i))
+		}
+
+	case reflect.Slice:
+		if o.equateEmpty && a.Len() == 0 && b.Len() == 0 {
+			return
+		}
+		if a.IsNil() != b.IsNil() {
+			o.report(path, "%s != %s", deepFormat(a), deepFormat(b))
+			return
+		}
+		if a.Pointer() == b.Pointer() && a.Len() == b.Len() {
+			return
+		}
+		if o.seen(a, b) {
+			return
+		}
+		a, b = o.sorted(a), o.sorted(b)
+		n := a.Len()
+		if b.Len() > n {
+			n = b.Len()
+		}
+		for i := 0; i < n; i++ {
+			p := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= a.Len():
+				o.report(p, "<missing> != %s", deepFormat(b.Index(i)))
+			case i >= b.Len():
+				o.report(p, "%s != <missing>", deepFormat(a.Index(i)))
+			default:
+				o.walk(p, a.Index(i), b.Index(i))
+			}
+		}
+
+	case reflect.Map:
+		if o.equateEmpty && a.Len() == 0 && b.Len() == 0 {
+			return
+		}
+		if a.IsNil() != b.IsNil() {
+			o.report(path, "%s != %s", deepFormat(a), deepFormat(b))
+			return
+		}
+		if a.Pointer() == b.Pointer() || o.seen(a, b) {
+			return
+		}
+		for _, key := range deepMapKeys(a, b) {
+			p := fmt.Sprintf("%s[%s]", path, deepFormat(key))
+			va, vb := a.MapIndex(key), b.MapIndex(key)
+			switch {
+			case !va.IsValid():
+				o.report(p, "<missing> != %s", deepFormat(vb))
+			case !vb.IsValid():
+				o.report(p, "%s != <missing>", deepFormat(va))
+			default:
+				o.walk(p, va, vb)
+			}
+		}
+
+	default:
+		o.report(path, "cannot compare values of kind %v", a.Kind())
+	}
+}
+
+// equalFloats compares two numbers exactly or, if EquateApprox was given, with tolerance
+func (o *deepWalker) equalFloats(a, b float64) bool {
+	return a == b || (o.tol != nil && o.tol.Within(a, b))
+}
+
+// reportFloats records a difference between two numbers, including the error if EquateApprox was given
+func (o *deepWalker) reportFloats(path string, va, vb reflect.Value, a, b float64) {
+	if o.tol == nil {
+		o.report(path, "%s != %s", deepFormat(va), deepFormat(vb))
+		return
+	}
+	o.report(path, "%s != %s (error = %g; %v)", deepFormat(va), deepFormat(vb), o.tol.Distance(a, b), *o.tol)
+}
+
+// deepKeyText holds the formatted value of a map key that cannot be converted to interface{}
+type deepKeyText string
+
+// deepMapKeys returns the union of keys in maps a and b, sorted by their formatted value
+//  NOTE: keys are compared by value (not by their formatted value, which may be the same for
+//        distinct keys; e.g. 1 and 1.0 in a map[interface{}]int)
+func deepMapKeys(a, b reflect.Value) (keys []reflect.Value) {
+	index := make(map[interface{}]bool)
+	for _, m := range []reflect.Value{a, b} {
+		for _, key := range m.MapKeys() {
+			var id interface{} = deepKeyText(deepFormat(key))
+			if key.CanInterface() {
+				id = key.Interface()
+			}
+			if !index[id] {
+				index[id] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		return deepFormat(keys[i]) < deepFormat(keys[j])
+	})
+	return
+}
+
+// deepFormat formats value for a message
+//  NOTE: strings are quoted; invalid values and nil references are printed as <nil>
+func deepFormat(v reflect.Value) string {
+	if !v.IsValid() {
+		return "<nil>"
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return fmt.Sprintf("%q", v.String())
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		if v.IsNil() {
+			return "<nil>"
+		}
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,235 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// maxDiffCells limits the size of the table used to find the longest common subsequence of lines
+const maxDiffCells = 4000000
+
+// ANSI colours
+const (
+	ansiRed   = "\x1b[31m"
+	ansiGreen = "\x1b[32m"
+	ansiCyan  = "\x1b[36m"
+	ansiReset = "\x1b[0m"
+)
+
+// DiffOptions holds options for computing line-oriented diffs
+type DiffOptions struct {
+	Context  int  // number of unchanged lines shown around each change
+	Color    bool // use ANSI colours
+	MaxLines int  // maximum number of output lines; 0 means no limit
+}
+
+// DefaultDiffOptions returns the default options for Diff
+//  NOTE: colours are enabled if the LOOTBAG_COLOR environment variable is set
+func DefaultDiffOptions() DiffOptions {
+	return DiffOptions{
+		Context:  3,
+		Color:    os.Getenv("LOOTBAG_COLOR") != "",
+		MaxLines: 500,
+	}
+}
+
+// Diff returns the unified diff between the lines of a and b using the default options
+//  NOTE: returns "" if a and b are equal
+func Diff(a, b string) string {
+	return DiffWith(a, b, DefaultDiffOptions())
+}
+
+// DiffWith returns the unified diff between the lines of a and b
+//  NOTE: (1) returns "" if a and b are equal
+//        (2) a line starting with "?" follows each pair of changed lines and
+//            marks with "^" the column of the first divergence
+func DiffWith(a, b string, opts DiffOptions) string {
+	if a == b {
+		return ""
+	}
+	ops := diffLines(strings.Split(a, "\n"), strings.Split(b, "\n"))
+	out := []string{
+		opts.paint(ansiRed, "--- a"),
+		opts.paint(ansiGreen, "+++ b"),
+	}
+	for _, h := range diffHunks(ops, opts.Context) {
+		out = append(out, opts.hunk(ops[h[0]:h[1]])...)
+	}
+	if opts.MaxLines > 0 && len(out) > opts.MaxLines {
+		rest := len(out) - opts.MaxLines
+		out = append(out[:opts.MaxLines], fmt.Sprintf("... (%d more lines)", rest))
+	}
+	return strings.Join(out, "\n") + "\n"
+}
+
+// diffOp holds one line of an edit script
+type diffOp struct {
+	kind byte   // ' ' (equal), '-' (only in a) or '+' (only in b)
+	ia   int    // index of line in a (or position in a for insertions)
+	ib   int    // index of line in b (or position in b for deletions)
+	text string // line
+}
+
+// diffLines computes an edit script transforming a into b
+func diffLines(a, b []string) (ops []diffOp) {
+
+	// common prefix and suffix
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		suf++
+	}
+	for i := 0; i < pre; i++ {
+		ops = append(ops, diffOp{' ', i, i, a[i]})
+	}
+
+	// longest common subsequence of the middle part
+	ma, mb := a[pre:len(a)-suf], b[pre:len(b)-suf]
+	n, m := len(ma), len(mb)
+	if n*m > maxDiffCells {
+		for i := 0; i < n; i++ {
+			ops = append(ops, diffOp{'-', pre + i, pre, ma[i]})
+		}
+		for j := 0; j < m; j++ {
+			ops = append(ops, diffOp{'+', pre + n, pre + j, mb[j]})
+		}
+	} else {
+		lcs := make([][]int32, n+1)
+		for i := range lcs {
+			lcs[i] = make([]int32, m+1)
+		}
+		for i := n - 1; i >= 0; i-- {
+			for j := m - 1; j >= 0; j-- {
+				if ma[i] == mb[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else if lcs[i+1][j] >= lcs[i][j+1] {
+					lcs[i][j] = lcs[i+1][j]
+				} else {
+					lcs[i][j] = lcs[i][j+1]
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < n || j < m {
+			switch {
+			case i < n && j < m && ma[i] == mb[j]:
+				ops = append(ops, diffOp{' ', pre + i, pre + j, ma[i]})
+				i++
+				j++
+			case j >= m || (i < n && lcs[i+1][j] >= lcs[i][j+1]):
+				ops = append(ops, diffOp{'-', pre + i, pre + j, ma[i]})
+				i++
+			default:
+				ops = append(ops, diffOp{'+', pre + i, pre + j, mb[j]})
+				j++
+			}
+		}
+	}
+
+	// common suffix
+	for k := 0; k < suf; k++ {
+		ia, ib := len(a)-suf+k, len(b)-suf+k
+		ops = append(ops, diffOp{' ', ia, ib, a[ia]})
+	}
+	return
+}
+
+// diffHunks returns the ranges [start,end) of ops that must be shown, with context lines
+func diffHunks(ops []diffOp, context int) (hunks [][2]int) {
+	for k, op := range ops {
+		if op.kind == ' ' {
+			continue
+		}
+		start, end := k-context, k+context+1
+		if start < 0 {
+			start = 0
+		}
+		if end > len(ops) {
+			end = len(ops)
+		}
+		if n := len(hunks); n > 0 && start <= hunks[n-1][1] {
+			hunks[n-1][1] = end
+			continue
+		}
+		hunks = append(hunks, [2]int{start, end})
+	}
+	return
+}
+
+// hunk formats a range of operations
+func (o DiffOptions) hunk(ops []diffOp) (out []string) {
+
+	// header
+	na, nb := 0, 0
+	for _, op := range ops {
+		if op.kind != '+' {
+			na++
+		}
+		if op.kind != '-' {
+			nb++
+		}
+	}
+	sa, sb := ops[0].ia+1, ops[0].ib+1
+	if na == 0 { // an empty range starts at the line before it (as in GNU diff)
+		sa--
+	}
+	if nb == 0 {
+		sb--
+	}
+	out = append(out, o.paint(ansiCyan, fmt.Sprintf("@@ -%d,%d +%d,%d @@", sa, na, sb, nb)))
+
+	// lines
+	for k := 0; k < len(ops); {
+		if ops[k].kind == ' ' {
+			out = append(out, " "+ops[k].text)
+			k++
+			continue
+		}
+
+		// block of deletions followed by block of insertions
+		var del, ins []string
+		for ; k < len(ops) && ops[k].kind == '-'; k++ {
+			del = append(del, ops[k].text)
+		}
+		for ; k < len(ops) && ops[k].kind == '+'; k++ {
+			ins = append(ins, ops[k].text)
+		}
+		for p := 0; p < len(del) || p < len(ins); p++ {
+			if p < len(del) {
+				out = append(out, o.paint(ansiRed, "-"+del[p]))
+			}
+			if p < len(ins) {
+				out = append(out, o.paint(ansiGreen, "+"+ins[p]))
+			}
+			if p < len(del) && p < len(ins) {
+				out = append(out, "?"+strings.Repeat(" ", divergence(del[p], ins[p]))+"^")
+			}
+		}
+	}
+	return
+}
+
+// paint wraps line with colour if colours are enabled
+func (o DiffOptions) paint(color, line string) string {
+	if !o.Color {
+		return line
+	}
+	return color + line + ansiReset
+}
+
+// divergence returns the column (in runes) of the first difference between a and b
+func divergence(a, b string) (col int) {
+	ra, rb := []rune(a), []rune(b)
+	for col < len(ra) && col < len(rb) && ra[col] == rb[col] {
+		col++
+	}
+	return
+}
@@ -0,0 +1,116 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// NoError checks whether err is nil
+func NoError(tst Reporter, msg string, err error) {
+	tst.Helper()
+	if err != nil {
+		fail(tst, msg, "unexpected error: %v\n%s", err, ErrorChain(err))
+		return
+	}
+	printOK(tst, msg)
+}
+
+// ErrorIs checks whether err or an error wrapped by it matches target; see errors.Is
+//  NOTE: use &check.Error{Code: code} as target to match errors by code; see also ErrorCode
+func ErrorIs(tst Reporter, msg string, err, target error) {
+	tst.Helper()
+	if err == nil {
+		fail(tst, msg, "no error; expected error matching %v\n", target)
+		return
+	}
+	if !errors.Is(err, target) {
+		fail(tst, msg, "error does not match %v\n%s", target, ErrorChain(err))
+		return
+	}
+	printOK(tst, msg)
+}
+
+// ErrorAs checks whether err or an error wrapped by it can be assigned to target; see errors.As
+//  NOTE: target must be a non-nil pointer to an interface or to a type implementing error;
+//        on success, target holds the matching error
+func ErrorAs(tst Reporter, msg string, err error, target interface{}) {
+	tst.Helper()
+	if err == nil {
+		fail(tst, msg, "no error; expected error of type %v\n", errorTargetType(target))
+		return
+	}
+	if !errors.As(err, target) {
+		fail(tst, msg, "no error of type %v\n%s", errorTargetType(target), ErrorChain(err))
+		return
+	}
+	printOK(tst, msg)
+}
+
+// ErrorContains checks whether the message of err contains substr
+func ErrorContains(tst Reporter, msg string, err error, substr string) {
+	tst.Helper()
+	if err == nil {
+		fail(tst, msg, "no error; expected error containing %q\n", substr)
+		return
+	}
+	if !strings.Contains(err.Error(), substr) {
+		fail(tst, msg, "error message does not contain %q\n%s", substr, ErrorChain(err))
+		return
+	}
+	printOK(tst, msg)
+}
+
+// ErrorCode checks whether err or an error wrapped by it is an *Error with the given code
+func ErrorCode(tst Reporter, msg string, err error, code string) {
+	tst.Helper()
+	if err == nil {
+		fail(tst, msg, "no error; expected error with code %q\n", code)
+		return
+	}
+	if !errors.Is(err, &Error{Code: code}) {
+		fail(tst, msg, "no error with code %q\n%s", code, ErrorChain(err))
+		return
+	}
+	printOK(tst, msg)
+}
+
+// ErrorChain returns a description of err and of the errors wrapped by it, one per line
+//
+//   Example:
+//     error chain:
+//       1) *check.Error [not-found] (at handler.go:42): not-found: cannot find user: EOF
+//       2) *errors.errorString: EOF
+//
+func ErrorChain(err error) string {
+	l := "error chain:\n"
+	for i := 1; err != nil; i++ {
+		l += fmt.Sprintf("  %d) %T", i, err)
+		if e, ok := err.(*Error); ok {
+			if e.Code != "" {
+				l += fmt.Sprintf(" [%s]", e.Code)
+			}
+			if e.Caller.File != "" {
+				l += fmt.Sprintf(" (at %s:%d)", filepath.Base(e.Caller.File), e.Caller.Line)
+			}
+		}
+		l += ": " + err.Error() + "\n"
+		err = errors.Unwrap(err)
+	}
+	return l
+}
+
+// errorTargetType returns the type pointed to by target (as given to errors.As)
+func errorTargetType(target interface{}) interface{} {
+	t := reflect.TypeOf(target)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return t
+	}
+	return t.Elem()
+}
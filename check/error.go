@@ -4,9 +4,69 @@
 
 package check
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
+
+// Error holds an error message, an optional machine-readable code,
+// the position where the error was created and an optional wrapped cause
+//  NOTE: works with errors.Is, errors.As and errors.Unwrap
+type Error struct {
+	Msg    string   // message, including the message of the cause (if any)
+	Code   string   // machine-readable code; e.g. "not-found" [may be empty]
+	Caller Caller   // position where the error was created
+	Stack  []Caller // stack of callers; captured by Panic [may be nil]
+	cause  error    // wrapped error [may be nil]
+}
 
 // Err returns a new error
+//  NOTE: the verb %w may be used to wrap another error
 func Err(msg string, prm ...interface{}) error {
-	return fmt.Errorf(msg, prm...)
+	return newError(3, "", fmt.Errorf(msg, prm...))
+}
+
+// ErrCode returns a new error with a machine-readable code
+func ErrCode(code, msg string, prm ...interface{}) error {
+	return newError(3, code, fmt.Errorf(msg, prm...))
+}
+
+// Wrap returns a new error wrapping cause; the message becomes "msg: cause"
+//  NOTE: returns nil if cause is nil
+func Wrap(cause error, msg string, prm ...interface{}) error {
+	if cause == nil {
+		return nil
+	}
+	return newError(3, "", fmt.Errorf("%s: %w", fmt.Sprintf(msg, prm...), cause))
+}
+
+// newError returns a new Error built from e
+//  idx -- index of the caller as in runtime.Caller; e.g. 3 for the caller of Err
+func newError(idx int, code string, e error) *Error {
+	return &Error{
+		Msg:    e.Error(),
+		Code:   code,
+		Caller: caller(idx),
+		cause:  errors.Unwrap(e),
+	}
+}
+
+// Error returns the error message, prefixed by the code if any
+func (o *Error) Error() string {
+	if o.Code == "" {
+		return o.Msg
+	}
+	return o.Code + ": " + o.Msg
+}
+
+// Unwrap returns the wrapped cause or nil
+func (o *Error) Unwrap() error {
+	return o.cause
+}
+
+// Is returns true if target is an *Error with the same (non-empty) code
+//   Example: errors.Is(err, &check.Error{Code: "not-found"})
+func (o *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code != "" && t.Code == o.Code
 }
@@ -0,0 +1,100 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// UpdateMode returns whether golden files must be rewritten
+//  NOTE: set by the LOOTBAG_UPDATE environment variable (e.g. LOOTBAG_UPDATE=1 go test ./...);
+//        a flag is not used because this package is also imported by non-test code
+func UpdateMode() bool {
+	return os.Getenv("LOOTBAG_UPDATE") != ""
+}
+
+// Normalizer transforms data before comparison with golden files
+type Normalizer func(data []byte) []byte
+
+// trailingSpace matches spaces and tabs at the end of lines
+var trailingSpace = regexp.MustCompile(`(?m)[ \t]+$`)
+
+// TrimTrailingSpace removes spaces and tabs at the end of each line
+func TrimTrailingSpace(data []byte) []byte {
+	return trailingSpace.ReplaceAll(data, nil)
+}
+
+// UnixNewlines converts "\r\n" and "\r" line endings to "\n"
+func UnixNewlines(data []byte) []byte {
+	data = bytes.Replace(data, []byte("\r\n"), []byte("\n"), -1)
+	return bytes.Replace(data, []byte("\r"), []byte("\n"), -1)
+}
+
+// CanonicalJSON re-encodes JSON data with sorted keys and indentation
+//  NOTE: data that is not a single valid JSON value (e.g. with trailing data) is returned unmodified
+func CanonicalJSON(data []byte) []byte {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return data
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return data
+	}
+	res, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return data
+	}
+	return append(res, '\n')
+}
+
+// Golden compares got with the content of testdata/<test>/<name>.golden
+//  NOTE: (1) the golden file is (re)written if UpdateMode() is true
+//        (2) normalizers are applied to both got and the golden file before comparison
+func Golden(tst Reporter, name string, got []byte, normalizers ...Normalizer) {
+	tst.Helper()
+	path := filepath.Join("testdata", filepath.FromSlash(tst.Name()), name+".golden")
+	golden(tst, path, got, UpdateMode(), normalizers...)
+}
+
+// golden implements Golden
+func golden(tst Reporter, path string, got []byte, update bool, normalizers ...Normalizer) {
+	tst.Helper()
+	for _, normalize := range normalizers {
+		got = normalize(got)
+	}
+	if update {
+		if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			fail(tst, path, "cannot create directory for golden file <%s>: %v\n", path, err)
+			return
+		}
+		if err := ioutil.WriteFile(path, got, 0644); err != nil {
+			fail(tst, path, "cannot write golden file <%s>: %v\n", path, err)
+			return
+		}
+		passf(tst, path, "golden file <%s> updated\n", path)
+		return
+	}
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		fail(tst, path, "cannot read golden file: %v\n(run tests with LOOTBAG_UPDATE=1 to create it)\n", err)
+		return
+	}
+	for _, normalize := range normalizers {
+		want = normalize(want)
+	}
+	if !bytes.Equal(got, want) {
+		fail(tst, path, "result differs from golden file <%s> (- golden, + got):\n%s", path, Diff(string(want), string(got)))
+		return
+	}
+	passf(tst, path, "%s: OK\n", path)
+}
@@ -0,0 +1,204 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// JSON placeholders that can be used as string values in the wanted JSON
+const (
+	JSONAny         = "<any>"   // matches any value
+	JSONRegexPrefix = "<regex:" // "<regex:PATTERN>" matches values whose text matches PATTERN
+)
+
+// jsonIdentifier matches keys that can be printed as .key in paths
+var jsonIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// JSON checks whether got and want represent the same JSON value
+//  NOTE: (1) objects are compared regardless of key order and formatting
+//        (2) numbers are compared by value; e.g. 1 == 1.0 == 1e0
+//        (3) want may contain the placeholders "<any>" and "<regex:PATTERN>"
+func JSON(tst Reporter, msg, got, want string) {
+	tst.Helper()
+	diffs, err := JSONDiff(got, want)
+	if err != nil {
+		fail(tst, msg, "%v\n", err)
+		return
+	}
+	if len(diffs) > 0 {
+		fail(tst, msg, "%s\n", strings.Join(diffs, "\n"))
+		return
+	}
+	printOK(tst, msg)
+}
+
+// JSONDiff returns the list of differences between the JSON values got and want; one entry per path
+//  NOTE: returns nil if got and want are equivalent; see JSON for comparison rules
+func JSONDiff(got, want string) (diffs []string, err error) {
+	g, err := jsonDecode(got)
+	if err != nil {
+		return nil, Err("cannot parse got JSON: %v", err)
+	}
+	w, err := jsonDecode(want)
+	if err != nil {
+		return nil, Err("cannot parse want JSON: %v", err)
+	}
+	jsonWalk(&diffs, "", g, w)
+	return
+}
+
+// jsonDecode decodes JSON data keeping numbers as json.Number
+func jsonDecode(data string) (v interface{}, err error) {
+	dec := json.NewDecoder(strings.NewReader(data))
+	dec.UseNumber()
+	if err = dec.Decode(&v); err != nil {
+		return
+	}
+	if _, e := dec.Token(); e != io.EOF { // dec.More is false before ']' and '}'
+		err = Err("unexpected data after JSON value")
+	}
+	return
+}
+
+// jsonWalk compares got and want at path
+func jsonWalk(diffs *[]string, path string, got, want interface{}) {
+
+	// report records a difference
+	report := func(format string, prm ...interface{}) {
+		text := fmt.Sprintf(format, prm...)
+		if path != "" {
+			text = path + ": " + text
+		}
+		*diffs = append(*diffs, text)
+	}
+
+	// placeholders
+	if s, ok := want.(string); ok {
+		if s == JSONAny {
+			return
+		}
+		if strings.HasPrefix(s, JSONRegexPrefix) && strings.HasSuffix(s, ">") {
+			pattern := s[len(JSONRegexPrefix) : len(s)-1]
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				report("invalid placeholder %q: %v", s, err)
+				return
+			}
+			text, isString := got.(string)
+			if !isString {
+				text = jsonFormat(got)
+			}
+			if !re.MatchString(text) {
+				report("%s does not match %s", jsonFormat(got), s)
+			}
+			return
+		}
+	}
+
+	// types
+	if jsonType(got) != jsonType(want) {
+		report("%s %s != %s %s", jsonType(got), jsonFormat(got), jsonType(want), jsonFormat(want))
+		return
+	}
+
+	switch w := want.(type) {
+
+	case json.Number:
+		g := got.(json.Number)
+		rg, okg := new(big.Rat).SetString(string(g))
+		rw, okw := new(big.Rat).SetString(string(w))
+		if !okg || !okw || rg.Cmp(rw) != 0 {
+			report("%s != %s", g, w)
+		}
+
+	case []interface{}:
+		g := got.([]interface{})
+		n := len(g)
+		if len(w) > n {
+			n = len(w)
+		}
+		for i := 0; i < n; i++ {
+			p := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(g):
+				*diffs = append(*diffs, fmt.Sprintf("%s: <missing> != %s", p, jsonFormat(w[i])))
+			case i >= len(w):
+				*diffs = append(*diffs, fmt.Sprintf("%s: %s != <missing>", p, jsonFormat(g[i])))
+			default:
+				jsonWalk(diffs, p, g[i], w[i])
+			}
+		}
+
+	case map[string]interface{}:
+		g := got.(map[string]interface{})
+		keys := make([]string, 0, len(g)+len(w))
+		for k := range g {
+			keys = append(keys, k)
+		}
+		for k := range w {
+			if _, ok := g[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			p := path + "." + k
+			if !jsonIdentifier.MatchString(k) {
+				p = fmt.Sprintf("%s[%q]", path, k)
+			}
+			vg, okg := g[k]
+			vw, okw := w[k]
+			switch {
+			case !okg:
+				*diffs = append(*diffs, fmt.Sprintf("%s: <missing> != %s", p, jsonFormat(vw)))
+			case !okw:
+				*diffs = append(*diffs, fmt.Sprintf("%s: %s != <missing>", p, jsonFormat(vg)))
+			default:
+				jsonWalk(diffs, p, vg, vw)
+			}
+		}
+
+	default:
+		if got != want {
+			report("%s != %s", jsonFormat(got), jsonFormat(want))
+		}
+	}
+}
+
+// jsonType returns the name of the JSON type of v
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	}
+	return "object"
+}
+
+// jsonFormat returns the compact JSON text of v
+func jsonFormat(v interface{}) string {
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
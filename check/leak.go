@@ -0,0 +1,130 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// leakTimeout is the time given to new goroutines to finish before they are reported as leaks
+const leakTimeout = 2 * time.Second
+
+// LeakIgnore holds substrings of stacks of goroutines that are never reported by NoLeaks
+//  NOTE: these are goroutines of the testing package, signal handling and the keep-alive
+//        connections of net/http clients
+var LeakIgnore = []string{
+	"testing.tRunner",
+	"testing.(*T).Run",
+	"testing.(*M).",
+	"testing.runTests",
+	"runtime.ensureSigM",
+	"os/signal.signal_recv",
+	"os/signal.loop",
+	"net/http.(*persistConn).readLoop",
+	"net/http.(*persistConn).writeLoop",
+}
+
+// NoLeaks checks whether the goroutines started after NoLeaks is called have finished
+// when the test ends
+//
+//   Input:
+//     ignore -- substrings of stacks of goroutines to ignore, in addition to LeakIgnore
+//
+//   Output:
+//     verify -- checks for leaks; it is registered with tst.Cleanup if tst has a Cleanup
+//               method (e.g. testing.TB); otherwise it must be called (e.g. deferred) by the caller
+//
+//   Example:
+//     func TestServer(tst *testing.T) {
+//         check.NoLeaks(tst)
+//         ...
+//     }
+//
+//  NOTE: new goroutines are given a short time to finish before being reported
+func NoLeaks(tst Reporter, ignore ...string) (verify func()) {
+	tst.Helper()
+	before := make(map[string]bool)
+	for _, g := range goroutines() {
+		before[g.id] = true
+	}
+	var once sync.Once
+	verify = func() {
+		once.Do(func() {
+			tst.Helper()
+			var leaked []goroutine
+			attempts, _ := WaitFor(leakTimeout, 10*time.Millisecond, func() error {
+				leaked = leaked[:0]
+				for _, g := range goroutines() {
+					if !before[g.id] && !g.ignored(ignore) {
+						leaked = append(leaked, g)
+					}
+				}
+				if len(leaked) > 0 {
+					return Err("%d goroutines leaked", len(leaked))
+				}
+				return nil
+			})
+			if len(leaked) > 0 {
+				l := fmt.Sprintf("%d goroutines leaked:\n", len(leaked))
+				for _, g := range leaked {
+					l += "\n" + g.stack + "\n"
+				}
+				fail(tst, "no leaks", "%s", l)
+				return
+			}
+			passf(tst, "no leaks", "no leaks: OK (%d attempts)\n", attempts)
+		})
+	}
+	if c, ok := tst.(interface{ Cleanup(func()) }); ok {
+		c.Cleanup(verify)
+	}
+	return
+}
+
+// goroutine holds the identifier and stack of a goroutine
+type goroutine struct {
+	id    string // e.g. "12"
+	stack string // as printed by runtime.Stack, including the "goroutine 12 [state]:" header
+}
+
+// ignored returns true if the stack matches LeakIgnore or extra
+func (o goroutine) ignored(extra []string) bool {
+	for _, list := range [][]string{LeakIgnore, extra} {
+		for _, s := range list {
+			if strings.Contains(o.stack, s) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// goroutines returns all goroutines but the current one
+func goroutines() (list []goroutine) {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	for k, stack := range strings.Split(string(buf), "\n\n") {
+		if k == 0 { // the current goroutine is printed first
+			continue
+		}
+		fields := strings.Fields(stack)
+		if len(fields) < 2 || fields[0] != "goroutine" {
+			continue
+		}
+		list = append(list, goroutine{id: fields[1], stack: strings.TrimSpace(stack)})
+	}
+	return
+}
@@ -0,0 +1,392 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Matcher defines a condition on values
+//
+//   The existing checks can be expressed with matchers; e.g.
+//     check.String(tst, msg, a, b)       ≡ check.That(tst, msg, a, check.Equal(b))
+//     check.Float64(tst, msg, tol, a, b) ≡ check.That(tst, msg, a, check.Near(b, tol))
+//
+type Matcher interface {
+	Match(value interface{}) error // returns nil if value satisfies the condition; otherwise explains why not
+	String() string                // describes the condition; e.g. `contains "abc"`
+}
+
+// That checks whether value satisfies the condition of matcher
+//
+//   Example:
+//     check.That(tst, "body", body, check.AllOf(check.HasPrefix("{"), check.Contains(`"success":true`)))
+//
+func That(tst Reporter, msg string, value interface{}, matcher Matcher) {
+	tst.Helper()
+	if err := matcher.Match(value); err != nil {
+		fail(tst, msg, "value: %s\nexpected: %s\nbut: %v\n", matchFormat(value), matcher, err)
+		return
+	}
+	printOK(tst, msg)
+}
+
+// matcher implements Matcher with a description and a function
+type matcher struct {
+	desc  string                    // description
+	match func(v interface{}) error // condition
+}
+
+func (o matcher) Match(v interface{}) error { return o.match(v) }
+func (o matcher) String() string            { return o.desc }
+
+// Equal matches values deeply equal to want; see DeepDiff
+func Equal(want interface{}) Matcher {
+	return matcher{"equal to " + matchFormat(want), func(v interface{}) error {
+		if diffs := DeepDiff(v, want); len(diffs) > 0 {
+			return Err("%s", strings.Join(diffs, "; "))
+		}
+		return nil
+	}}
+}
+
+// Near matches numbers within tol of want (absolute tolerance)
+func Near(want, tol float64) Matcher {
+	return matcher{fmt.Sprintf("within %v of %v", tol, want), func(v interface{}) error {
+		x, ok := matchFloat(v)
+		if !ok {
+			return Err("value of type %T is not a number", v)
+		}
+		if d := math.Abs(x - want); !(d <= tol) {
+			return Err("difference is %v", d)
+		}
+		return nil
+	}}
+}
+
+// Contains matches strings (or []byte, errors and fmt.Stringers) containing the substring elem,
+// and slices or arrays containing an element deeply equal to elem
+func Contains(elem interface{}) Matcher {
+	return matcher{"contains " + matchFormat(elem), func(v interface{}) error {
+		if s, ok := matchString(v); ok {
+			sub, ok := matchString(elem)
+			if !ok {
+				return Err("cannot search for %T in a string", elem)
+			}
+			if !strings.Contains(s, sub) {
+				return Err("substring not found")
+			}
+			return nil
+		}
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return Err("value of type %T cannot contain elements", v)
+		}
+		for i := 0; i < rv.Len(); i++ {
+			if DeepDiff(rv.Index(i).Interface(), elem) == nil {
+				return nil
+			}
+		}
+		return Err("element not found among %d elements", rv.Len())
+	}}
+}
+
+// HasPrefix matches strings (or []byte, errors and fmt.Stringers) starting with prefix
+func HasPrefix(prefix string) Matcher {
+	return matcher{fmt.Sprintf("has prefix %q", prefix), func(v interface{}) error {
+		s, ok := matchString(v)
+		if !ok {
+			return Err("value of type %T is not a string", v)
+		}
+		if !strings.HasPrefix(s, prefix) {
+			return Err("prefix not found")
+		}
+		return nil
+	}}
+}
+
+// Regex matches strings (or []byte, errors and fmt.Stringers) matching the regular expression pattern
+//  NOTE: panics if pattern is invalid
+func Regex(pattern string) Matcher {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		Panic("invalid regular expression %q: %v\n", pattern, err)
+	}
+	return matcher{fmt.Sprintf("matches regex %q", pattern), func(v interface{}) error {
+		s, ok := matchString(v)
+		if !ok {
+			return Err("value of type %T is not a string", v)
+		}
+		if !re.MatchString(s) {
+			return Err("regex does not match")
+		}
+		return nil
+	}}
+}
+
+// Len matches strings, slices, arrays, maps and channels with n elements
+func Len(n int) Matcher {
+	return matcher{fmt.Sprintf("has length %d", n), func(v interface{}) error {
+		l, err := matchLen(v)
+		if err != nil {
+			return err
+		}
+		if l != n {
+			return Err("has length %d", l)
+		}
+		return nil
+	}}
+}
+
+// Empty matches strings, slices, arrays, maps and channels without elements (including nil ones)
+func Empty() Matcher {
+	return matcher{"is empty", func(v interface{}) error {
+		l, err := matchLen(v)
+		if err != nil {
+			return err
+		}
+		if l != 0 {
+			return Err("has length %d", l)
+		}
+		return nil
+	}}
+}
+
+// Nil matches nil values, including nil pointers, slices, maps, functions, channels and interfaces
+func Nil() Matcher {
+	return matcher{"is nil", func(v interface{}) error {
+		if !matchNil(v) {
+			return Err("is not nil")
+		}
+		return nil
+	}}
+}
+
+// SameElements matches slices or arrays with the same elements as want, in any order
+//  NOTE: (1) elements are compared deeply and repeated elements must appear the same number of times
+//        (2) this matcher is named SameElements, not ElementsMatch, because the name ElementsMatch
+//            is used by the check function with the same semantics (as in other assertion libraries)
+func SameElements(want interface{}) Matcher {
+	return matcher{"has elements " + matchFormat(want) + " in any order", func(v interface{}) error {
+		missing, extra, err := elementsDiff(v, want)
+		if err != nil {
+			return err
+		}
+		if len(missing) > 0 || len(extra) > 0 {
+			return Err("%s", strings.Join(elementsReport(missing, extra), "; "))
+		}
+		return nil
+	}}
+}
+
+// HasKey matches maps with the key
+func HasKey(key interface{}) Matcher {
+	return matcher{"has key " + matchFormat(key), func(v interface{}) error {
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Map {
+			return Err("value of type %T is not a map", v)
+		}
+		k := reflect.ValueOf(key)
+		if !k.IsValid() || !k.Type().AssignableTo(rv.Type().Key()) {
+			return Err("key of type %T cannot be used with %T", key, v)
+		}
+		if !rv.MapIndex(k).IsValid() {
+			return Err("key not found among %d keys", rv.Len())
+		}
+		return nil
+	}}
+}
+
+// Not matches values that do not satisfy m
+func Not(m Matcher) Matcher {
+	return matcher{"not " + m.String(), func(v interface{}) error {
+		if m.Match(v) == nil {
+			return Err("it does")
+		}
+		return nil
+	}}
+}
+
+// AllOf matches values satisfying all matchers
+func AllOf(matchers ...Matcher) Matcher {
+	return matcher{"all of (" + matchDescs(matchers) + ")", func(v interface{}) error {
+		var reasons []string
+		for _, m := range matchers {
+			if err := m.Match(v); err != nil {
+				reasons = append(reasons, fmt.Sprintf("%s: %v", m, err))
+			}
+		}
+		if len(reasons) > 0 {
+			return Err("%s", strings.Join(reasons, "; "))
+		}
+		return nil
+	}}
+}
+
+// AnyOf matches values satisfying at least one of the matchers
+func AnyOf(matchers ...Matcher) Matcher {
+	return matcher{"any of (" + matchDescs(matchers) + ")", func(v interface{}) error {
+		var reasons []string
+		for _, m := range matchers {
+			err := m.Match(v)
+			if err == nil {
+				return nil
+			}
+			reasons = append(reasons, fmt.Sprintf("%s: %v", m, err))
+		}
+		return Err("%s", strings.Join(reasons, "; "))
+	}}
+}
+
+// matchDescs joins the descriptions of matchers
+func matchDescs(matchers []Matcher) string {
+	descs := make([]string, len(matchers))
+	for i, m := range matchers {
+		descs[i] = m.String()
+	}
+	return strings.Join(descs, "; ")
+}
+
+// matchFormat formats a value for messages; e.g. strings are quoted
+func matchFormat(v interface{}) string {
+	return deepFormat(reflect.ValueOf(v))
+}
+
+// matchString converts strings, []byte, errors and fmt.Stringers to string
+func matchString(v interface{}) (s string, ok bool) {
+	switch x := v.(type) {
+	case string:
+		return x, true
+	case []byte:
+		return string(x), true
+	case error:
+		return x.Error(), true
+	case fmt.Stringer:
+		return x.String(), true
+	}
+	return "", false
+}
+
+// matchFloat converts numbers to float64
+func matchFloat(v interface{}) (x float64, ok bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// matchLen returns the number of elements of v
+func matchLen(v interface{}) (int, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+		return rv.Len(), nil
+	}
+	return 0, Err("value of type %T has no length", v)
+}
+
+// matchNil returns whether v is nil or holds a nil reference
+func matchNil(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// elementsDiff compares the elements of two slices or arrays regardless of order
+//
+//   Output:
+//     missing -- elements of want not found in got
+//     extra -- elements of got not found in want
+//
+//  NOTE: (1) each element of got is paired with at most one deeply equal element of want
+//        (2) nil is treated as an empty slice
+func elementsDiff(got, want interface{}) (missing, extra []interface{}, err error) {
+	if got == nil {
+		got = []interface{}{}
+	}
+	if want == nil {
+		want = []interface{}{}
+	}
+	for _, v := range []interface{}{got, want} {
+		if k := reflect.ValueOf(v).Kind(); k != reflect.Slice && k != reflect.Array {
+			return nil, nil, Err("value of type %T is not a slice or array", v)
+		}
+	}
+	g, w := reflect.ValueOf(got), reflect.ValueOf(want)
+	used := make([]bool, w.Len())
+	for i := 0; i < g.Len(); i++ {
+		found := false
+		for j := 0; j < w.Len(); j++ {
+			if !used[j] && DeepDiff(g.Index(i).Interface(), w.Index(j).Interface()) == nil {
+				used[j], found = true, true
+				break
+			}
+		}
+		if !found {
+			extra = append(extra, g.Index(i).Interface())
+		}
+	}
+	for j := 0; j < w.Len(); j++ {
+		if !used[j] {
+			missing = append(missing, w.Index(j).Interface())
+		}
+	}
+	return
+}
+
+// elementsReport describes missing and extra elements, grouping repeated ones; e.g. `missing: "a" (×2)`
+func elementsReport(missing, extra []interface{}) (lines []string) {
+	for _, group := range []struct {
+		label string
+		list  []interface{}
+	}{{"missing", missing}, {"extra", extra}} {
+		if len(group.list) == 0 {
+			continue
+		}
+		var items []string
+		var counts []int
+		for _, v := range group.list {
+			s := matchFormat(v)
+			k := 0
+			for k < len(items) && items[k] != s {
+				k++
+			}
+			if k == len(items) {
+				items = append(items, s)
+				counts = append(counts, 0)
+			}
+			counts[k]++
+		}
+		var buf bytes.Buffer
+		buf.WriteString(group.label + ": ")
+		for k, s := range items {
+			if k > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(s)
+			if counts[k] > 1 {
+				fmt.Fprintf(&buf, " (×%d)", counts[k])
+			}
+		}
+		lines = append(lines, buf.String())
+	}
+	return
+}
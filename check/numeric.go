@@ -0,0 +1,242 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"fmt"
+	"math"
+)
+
+// tolerance modes
+const (
+	tolAbsolute = iota
+	tolRelative
+	tolULP
+)
+
+// Tolerance defines how close two finite float64 numbers must be to be considered equal
+type Tolerance struct {
+	mode  int     // absolute, relative or ULP distance
+	value float64 // maximum error
+}
+
+// AbsTol returns an absolute tolerance: |a-b| ≤ tol
+func AbsTol(tol float64) Tolerance {
+	return Tolerance{tolAbsolute, tol}
+}
+
+// RelTol returns a relative tolerance: |a-b| ≤ tol·max(|a|,|b|)
+func RelTol(tol float64) Tolerance {
+	return Tolerance{tolRelative, tol}
+}
+
+// UlpTol returns a tolerance given by the maximum number of representable
+// float64 numbers (units in the last place) between a and b
+func UlpTol(maxUlps uint64) Tolerance {
+	return Tolerance{tolULP, float64(maxUlps)}
+}
+
+// String returns a description of the tolerance
+func (o Tolerance) String() string {
+	switch o.mode {
+	case tolRelative:
+		return fmt.Sprintf("relative tolerance %g", o.value)
+	case tolULP:
+		return fmt.Sprintf("ULP tolerance %g", o.value)
+	}
+	return fmt.Sprintf("absolute tolerance %g", o.value)
+}
+
+// Distance returns the error between a and b as measured by this tolerance
+func (o Tolerance) Distance(a, b float64) float64 {
+	switch o.mode {
+	case tolRelative:
+		scale := math.Max(math.Abs(a), math.Abs(b))
+		if scale == 0 {
+			return 0
+		}
+		return math.Abs(a-b) / scale
+	case tolULP:
+		return float64(ulpDistance(a, b))
+	}
+	return math.Abs(a - b)
+}
+
+// Within returns true if a and b are within tolerance
+func (o Tolerance) Within(a, b float64) bool {
+	return o.Distance(a, b) <= o.value
+}
+
+// ulpDistance returns the number of representable float64 numbers between a and b
+func ulpDistance(a, b float64) uint64 {
+	ia, ib := orderedBits(a), orderedBits(b)
+	if ia > ib {
+		return uint64(ia) - uint64(ib)
+	}
+	return uint64(ib) - uint64(ia)
+}
+
+// orderedBits maps the bits of x to an integer that is monotonic with x
+func orderedBits(x float64) int64 {
+	i := int64(math.Float64bits(x))
+	if i < 0 {
+		i = math.MinInt64 - i
+	}
+	return i
+}
+
+// notFinite returns a message if a or b is NaN or Inf; otherwise returns ""
+//  at -- position of the values (e.g. "[3]"); may be ""
+func notFinite(at string, a, b float64) string {
+	if math.IsNaN(a) {
+		return fmt.Sprintf("a%s=%v is NaN\n", at, a)
+	}
+	if math.IsInf(a, 0) {
+		return fmt.Sprintf("a%s=%v is Inf\n", at, a)
+	}
+	if math.IsNaN(b) {
+		return fmt.Sprintf("b%s=%v is NaN\n", at, b)
+	}
+	if math.IsInf(b, 0) {
+		return fmt.Sprintf("b%s=%v is Inf\n", at, b)
+	}
+	return ""
+}
+
+// floatCmp compares many pairs of numbers and keeps track of mismatches
+type floatCmp struct {
+	tol    Tolerance // tolerance
+	count  int       // number of mismatches
+	first  string    // message describing the first mismatch
+	maxErr float64   // maximum error
+	maxAt  string    // position of maximum error
+}
+
+// compare compares a and b at position 'at'
+//  NOTE: returns a message (and stops the comparison) if a or b is not finite
+func (o *floatCmp) compare(at string, a, b float64) (stop string) {
+	if stop = notFinite(at, a, b); stop != "" {
+		return
+	}
+	err := o.tol.Distance(a, b)
+	if err > o.maxErr || o.maxAt == "" {
+		o.maxErr, o.maxAt = err, at
+	}
+	if err > o.tol.value {
+		if o.count == 0 {
+			o.first = fmt.Sprintf("a%s=%v != b%s=%v", at, a, at, b)
+		}
+		o.count++
+	}
+	return
+}
+
+// failed returns a message describing the mismatches; or "" if there are none
+func (o *floatCmp) failed() string {
+	if o.count == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s\n(%d mismatches; max error = %g at %s; %v)\n", o.first, o.count, o.maxErr, o.maxAt, o.tol)
+}
+
+// Float64Tol checks float64 using a given tolerance mode
+func Float64Tol(tst Reporter, msg string, tol Tolerance, a, b float64) {
+	tst.Helper()
+	if stop := notFinite("", a, b); stop != "" {
+		fail(tst, msg, "%s", stop)
+		return
+	}
+	if !tol.Within(a, b) {
+		fail(tst, msg, "%v != %v (error = %g; %v)\n", a, b, tol.Distance(a, b), tol)
+		return
+	}
+	printOK(tst, msg)
+}
+
+// Float64s checks slice of float64 using an absolute tolerance
+func Float64s(tst Reporter, msg string, tol float64, a, b []float64) {
+	tst.Helper()
+	Float64sTol(tst, msg, AbsTol(tol), a, b)
+}
+
+// Float64sTol checks slice of float64 using a given tolerance mode
+func Float64sTol(tst Reporter, msg string, tol Tolerance, a, b []float64) {
+	tst.Helper()
+	if len(a) != len(b) {
+		fail(tst, msg, "len(a)=%d != len(b)=%d\n", len(a), len(b))
+		return
+	}
+	cmp := &floatCmp{tol: tol}
+	for i := 0; i < len(a); i++ {
+		if stop := cmp.compare(fmt.Sprintf("[%d]", i), a[i], b[i]); stop != "" {
+			fail(tst, msg, "%s", stop)
+			return
+		}
+	}
+	if res := cmp.failed(); res != "" {
+		fail(tst, msg, "%s", res)
+		return
+	}
+	printOK(tst, msg)
+}
+
+// Deep2 checks matrix of float64 using an absolute tolerance
+func Deep2(tst Reporter, msg string, tol float64, a, b [][]float64) {
+	tst.Helper()
+	Deep2Tol(tst, msg, AbsTol(tol), a, b)
+}
+
+// Deep2Tol checks matrix of float64 using a given tolerance mode
+func Deep2Tol(tst Reporter, msg string, tol Tolerance, a, b [][]float64) {
+	tst.Helper()
+	if len(a) != len(b) {
+		fail(tst, msg, "len(a)=%d != len(b)=%d\n", len(a), len(b))
+		return
+	}
+	cmp := &floatCmp{tol: tol}
+	for i := 0; i < len(a); i++ {
+		if len(a[i]) != len(b[i]) {
+			fail(tst, msg, "len(a[%d])=%d != len(b[%d])=%d\n", i, len(a[i]), i, len(b[i]))
+			return
+		}
+		for j := 0; j < len(a[i]); j++ {
+			if stop := cmp.compare(fmt.Sprintf("[%d][%d]", i, j), a[i][j], b[i][j]); stop != "" {
+				fail(tst, msg, "%s", stop)
+				return
+			}
+		}
+	}
+	if res := cmp.failed(); res != "" {
+		fail(tst, msg, "%s", res)
+		return
+	}
+	printOK(tst, msg)
+}
+
+// Complex128s checks slice of complex128 using an absolute tolerance
+//  NOTE: the real and imaginary parts are compared separately
+func Complex128s(tst Reporter, msg string, tol float64, a, b []complex128) {
+	tst.Helper()
+	if len(a) != len(b) {
+		fail(tst, msg, "len(a)=%d != len(b)=%d\n", len(a), len(b))
+		return
+	}
+	cmp := &floatCmp{tol: AbsTol(tol)}
+	for i := 0; i < len(a); i++ {
+		if stop := cmp.compare(fmt.Sprintf("[%d].real", i), real(a[i]), real(b[i])); stop != "" {
+			fail(tst, msg, "%s", stop)
+			return
+		}
+		if stop := cmp.compare(fmt.Sprintf("[%d].imag", i), imag(a[i]), imag(b[i])); stop != "" {
+			fail(tst, msg, "%s", stop)
+			return
+		}
+	}
+	if res := cmp.failed(); res != "" {
+		fail(tst, msg, "%s", res)
+		return
+	}
+	printOK(tst, msg)
+}
@@ -8,34 +8,64 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"regexp"
 	"runtime"
-	"testing"
+	"strings"
 )
 
+// Caller holds a position in the source code
+type Caller struct {
+	File string // full path of the file
+	Line int    // line number
+	Func string // fully qualified name of the function
+}
+
+// String returns "file:line func"
+func (o Caller) String() string {
+	return fmt.Sprintf("%s:%d %s", o.File, o.Line, o.Func)
+}
+
 // CallerInfo returns the file and line positions where an error occurred
 //  idx -- use idx=2 to get the caller of Panic
-func CallerInfo(idx int) {
+//  NOTE: the position is also logged with LevelVerbose
+func CallerInfo(idx int) (c Caller) {
+	c = caller(idx + 1)
+	if Verbosity() >= LevelVerbose {
+		log.Printf("file = %s:%d\n", c.File, c.Line)
+		log.Printf("func = %s\n", c.Func)
+	}
+	return
+}
+
+// caller returns the position of a caller as in runtime.Caller(idx)
+func caller(idx int) (c Caller) {
 	pc, file, line, ok := runtime.Caller(idx)
 	if !ok {
 		file, line = "?", 0
 	}
-	var fname string
+	c.File, c.Line = file, line
 	f := runtime.FuncForPC(pc)
 	if f != nil {
-		fname = f.Name()
-	}
-	if verboseMode {
-		log.Printf("file = %s:%d\n", file, line)
-		log.Printf("func = %s\n", fname)
+		c.Func = f.Name()
 	}
+	return
 }
 
-// Panic calls CallerInfo and panicks
+// Panic panics with an *Error holding the message and the stack of callers
+//  NOTE: (1) the recovered value is an *Error, not a string as in previous versions; thus,
+//            use fmt.Sprint(r) or r.(error).Error() instead of r.(string) to get the message
+//        (2) the caller position is logged with LevelVerbose and the stack with LevelDebug
 func Panic(msg string, prm ...interface{}) {
-	CallerInfo(4)
-	CallerInfo(3)
-	CallerInfo(2)
-	panic(fmt.Sprintf(msg, prm...))
+	e := newError(3, "", fmt.Errorf(msg, prm...))
+	e.Stack = CaptureStack(1)
+	e.Caller = outsideCheck(e.Stack)
+	switch l := Verbosity(); {
+	case l >= LevelDebug:
+		log.Printf("panic: %v\n%s", e, FormatStack(e.Stack))
+	case l >= LevelVerbose:
+		log.Printf("panic: %v\n    at %v\n", e, e.Caller)
+	}
+	panic(e)
 }
 
 // MaybePanic calls Panic if doPanic is true; otherwise it does nothing
@@ -46,25 +76,74 @@ func MaybePanic(doPanic bool, msg string, prm ...interface{}) {
 }
 
 // Recover catches panics and call os.Exit(1) on 'panic'
+//  NOTE: the stack trace is printed as well
 func Recover() {
 	if err := recover(); err != nil {
-		fmt.Printf("ERROR: %v\n", err)
+		fmt.Printf("ERROR: %v\n%s", err, FormatStack(panicStack(err)))
 		os.Exit(1)
 	}
 }
 
 // RecoverTst catches panics in tests. Test will fail on 'panic'
-func RecoverTst(tst *testing.T) {
+//  NOTE: the stack trace is reported as well
+func RecoverTst(tst Reporter) {
+	tst.Helper()
 	if err := recover(); err != nil {
-		tst.Errorf("%v\n", err)
+		fail(tst, "recover", "%v\n%s", err, FormatStack(panicStack(err)))
 		tst.FailNow()
 	}
 }
 
 // RecoverTstPanicIsOK catches panics in tests. Test must 'panic' to be OK
-func RecoverTstPanicIsOK(tst *testing.T) {
+func RecoverTstPanicIsOK(tst Reporter) {
+	tst.Helper()
 	if err := recover(); err == nil {
-		tst.Errorf("Test should have panicked\n")
+		fail(tst, "panic", "Test should have panicked\n")
 		tst.FailNow()
 	}
 }
+
+// Panics checks whether fn panics and returns the recovered value
+func Panics(tst Reporter, msg string, fn func()) (value interface{}) {
+	tst.Helper()
+	value, panicked := capturePanic(fn)
+	if !panicked {
+		fail(tst, msg, "function should have panicked\n")
+		return
+	}
+	printOK(tst, msg)
+	return
+}
+
+// PanicsWith checks whether fn panics with a value whose text matches pattern and returns the recovered value
+//  pattern -- a substring of the panic message or a regular expression; e.g. "cannot parse" or "^cannot .* int"
+func PanicsWith(tst Reporter, msg, pattern string, fn func()) (value interface{}) {
+	tst.Helper()
+	value, panicked := capturePanic(fn)
+	if !panicked {
+		fail(tst, msg, "function should have panicked\n")
+		return
+	}
+	text := fmt.Sprint(value)
+	if !strings.Contains(text, pattern) {
+		re, err := regexp.Compile(pattern)
+		if err != nil || !re.MatchString(text) {
+			fail(tst, msg, "panic message %q does not match %q\n", text, pattern)
+			return
+		}
+	}
+	printOK(tst, msg)
+	return
+}
+
+// capturePanic calls fn and returns the recovered value, if fn panicked
+//  NOTE: panicked is true even if fn calls panic(nil)
+func capturePanic(fn func()) (value interface{}, panicked bool) {
+	panicked = true
+	defer func() {
+		value = recover()
+	}()
+	fn()
+	panicked = false
+	return
+}
@@ -4,21 +4,153 @@
 
 package check
 
-import "fmt"
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
 
-// verboseMode controls message level
-var verboseMode = false
+// Level defines how many messages are printed
+type Level int32
+
+// verbosity levels
+const (
+	LevelQuiet   Level = iota // no messages at all
+	LevelNormal               // test titles only
+	LevelVerbose              // also "OK" messages, lio.Pf output and caller positions
+	LevelDebug                // also stack traces of panics
+)
+
+// levelUnset indicates that the global level has not been set
+const levelUnset = -1
+
+// globalLevel holds the level set by SetVerbosity or Verbose
+var globalLevel int32 = levelUnset
+
+// defaultLevel caches the level given by the environment; see Verbosity
+var defaultLevel int32 = levelUnset
+
+// testLevels maps test names to levels set by SetTestVerbosity
+var testLevels sync.Map
+
+// verbosityKey is the context key for levels set by WithVerbosity
+type verbosityKey struct{}
 
 // Verbose is an auxiliary function to set verbose mode
+//  NOTE: same as SetVerbosity(LevelVerbose); kept for compatibility
 func Verbose() {
-	verboseMode = true
+	SetVerbosity(LevelVerbose)
+}
+
+// SetVerbosity sets the global level
+func SetVerbosity(l Level) {
+	atomic.StoreInt32(&globalLevel, int32(l))
+}
+
+// Verbosity returns the global level
+//  NOTE: (1) if SetVerbosity has not been called, the level is given by the LOOTBAG_VERBOSE
+//            environment variable (quiet, normal, verbose, debug or 0 to 3); otherwise
+//            it is LevelVerbose if tests are run with -v and LevelNormal if not
+//        (2) the level given by the environment is found once (after the flags are parsed)
+func Verbosity() Level {
+	if l := atomic.LoadInt32(&globalLevel); l != levelUnset {
+		return Level(l)
+	}
+	if l := atomic.LoadInt32(&defaultLevel); l != levelUnset {
+		return Level(l)
+	}
+	l, ok := ParseLevel(os.Getenv("LOOTBAG_VERBOSE"))
+	f := flag.Lookup("test.v")
+	if !ok {
+		l = LevelNormal
+		if f != nil && (f.Value.String() == "true" || f.Value.String() == "test2json") {
+			l = LevelVerbose
+		}
+	}
+	if ok || f == nil || flag.Parsed() {
+		atomic.StoreInt32(&defaultLevel, int32(l))
+	}
+	return l
+}
+
+// ParseLevel converts a string such as "verbose" or "2" to Level
+func ParseLevel(s string) (l Level, ok bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "0", "quiet":
+		return LevelQuiet, true
+	case "1", "normal", "false":
+		return LevelNormal, true
+	case "2", "verbose", "true":
+		return LevelVerbose, true
+	case "3", "debug":
+		return LevelDebug, true
+	}
+	return LevelNormal, false
+}
+
+// SetTestVerbosity sets the level of a test (and its subtests)
+//  NOTE: (1) the level is removed when the test finishes if tst has a Cleanup method (e.g. testing.TB)
+//        (2) only functions receiving tst (e.g. the check functions) use this level; functions
+//            without a test, such as lio.Pf and lio.TestTitle, use the global level (Verbosity)
+func SetTestVerbosity(tst Reporter, l Level) {
+	name := tst.Name()
+	testLevels.Store(name, l)
+	if c, ok := tst.(interface{ Cleanup(func()) }); ok {
+		c.Cleanup(func() { testLevels.Delete(name) })
+	}
+}
+
+// TestVerbosity returns the level of a test
+//  NOTE: falls back to the level of the parent test and then to the global level
+func TestVerbosity(tst Reporter) Level {
+	name := tst.Name()
+	for {
+		if l, ok := testLevels.Load(name); ok {
+			return l.(Level)
+		}
+		i := strings.LastIndex(name, "/")
+		if i < 0 {
+			return Verbosity()
+		}
+		name = name[:i]
+	}
+}
+
+// WithVerbosity returns a copy of ctx holding level l
+func WithVerbosity(ctx context.Context, l Level) context.Context {
+	return context.WithValue(ctx, verbosityKey{}, l)
+}
+
+// ContextVerbosity returns the level held by ctx; or the global level if there is none
+func ContextVerbosity(ctx context.Context) Level {
+	if l, ok := ctx.Value(verbosityKey{}).(Level); ok {
+		return l
+	}
+	return Verbosity()
+}
+
+// printOK records a successful assertion and prints "msg: OK" if the level of the test is LevelVerbose or higher
+func printOK(tst Reporter, msg string) {
+	passf(tst, msg, "%s: OK\n", msg)
+}
+
+// verbosef prints a formatted message if the level of the test is LevelVerbose or higher
+func verbosef(tst Reporter, msg string, prm ...interface{}) {
+	if TestVerbosity(tst) >= LevelVerbose {
+		fmt.Printf(msg, prm...)
+	}
 }
 
 // testTitle prints title of test
 func testTitle(title string) {
-	if verboseMode {
+	switch l := Verbosity(); {
+	case l >= LevelVerbose:
 		fmt.Printf("\n=== %s =================\n", title)
-		return
+	case l >= LevelNormal:
+		fmt.Printf("   . . . testing . . .   %s\n", title)
 	}
-	fmt.Printf("   . . . testing . . .   %s\n", title)
 }
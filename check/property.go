@@ -0,0 +1,514 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Generator generates random values of a given type and simplifies (shrinks) them
+type Generator interface {
+	Type() reflect.Type                     // type of generated values
+	Generate(rnd *rand.Rand) interface{}    // returns a new random value
+	Shrink(value interface{}) []interface{} // returns simpler candidates; empty if value is minimal
+}
+
+// PropertyConfig holds options for Property
+type PropertyConfig struct {
+	Runs       int   // number of random inputs to try
+	Seed       int64 // seed of the random numbers generator; 0 means LOOTBAG_SEED or the current time
+	MaxShrinks int   // maximum number of attempts to shrink a counterexample
+}
+
+// DefaultPropertyConfig returns the default options for Property
+func DefaultPropertyConfig() PropertyConfig {
+	return PropertyConfig{Runs: 100, MaxShrinks: 1000}
+}
+
+// Property checks whether fn holds for random inputs created by the generators
+//
+//   fn -- a function with one argument per generator returning bool or error;
+//         false, a non-nil error or a panic mean that the property does not hold
+//
+//   Example:
+//     check.Property(tst, func(n int) bool {
+//         return lio.Atoi(strconv.Itoa(n)) == n
+//     }, check.GenInt(-1000, 1000))
+//
+//   NOTE: on failure, the input is shrunk to a minimal counterexample and the
+//         seed is reported; rerun with LOOTBAG_SEED=<seed> to reproduce it
+//
+func Property(tst Reporter, fn interface{}, generators ...Generator) {
+	tst.Helper()
+	PropertyWith(tst, DefaultPropertyConfig(), fn, generators...)
+}
+
+// PropertyWith checks whether fn holds for random inputs created by the generators; see Property
+func PropertyWith(tst Reporter, cfg PropertyConfig, fn interface{}, generators ...Generator) {
+	tst.Helper()
+
+	// check function
+	f := reflect.ValueOf(fn)
+	if err := propertyCheckFunc(f, generators); err != nil {
+		fail(tst, "property", "%v\n", err)
+		return
+	}
+
+	// seed
+	seed := cfg.Seed
+	if seed == 0 {
+		seed, _ = strconv.ParseInt(os.Getenv("LOOTBAG_SEED"), 10, 64)
+	}
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rnd := rand.New(rand.NewSource(seed))
+
+	// run
+	for run := 1; run <= cfg.Runs; run++ {
+		args := make([]interface{}, len(generators))
+		for i, g := range generators {
+			args[i] = g.Generate(rnd)
+		}
+		reason := propertyCall(f, args)
+		if reason == "" {
+			continue
+		}
+		original := propertyFormat(args)
+		args, reason, shrinks := propertyShrink(f, generators, args, reason, cfg.MaxShrinks)
+		fail(tst, "property", "property does not hold after %d runs (seed %d; rerun with LOOTBAG_SEED=%d)\ncounterexample: %s\noriginal input: %s (%d shrinks)\nreason: %s\n",
+			run, seed, seed, propertyFormat(args), original, shrinks, reason)
+		return
+	}
+	passf(tst, "property", "property: OK (%d runs; seed %d)\n", cfg.Runs, seed)
+}
+
+// propertyCheckFunc checks whether f can be called with values created by the generators
+func propertyCheckFunc(f reflect.Value, generators []Generator) error {
+	if f.Kind() != reflect.Func {
+		return Err("property must be a function; got %v", f.Kind())
+	}
+	t := f.Type()
+	if t.NumIn() != len(generators) {
+		return Err("property has %d arguments but %d generators were given", t.NumIn(), len(generators))
+	}
+	for i, g := range generators {
+		if !g.Type().AssignableTo(t.In(i)) {
+			return Err("generator %d creates %v but argument %d of property is %v", i, g.Type(), i, t.In(i))
+		}
+	}
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+	if t.NumOut() != 1 || (t.Out(0).Kind() != reflect.Bool && t.Out(0) != errorType) {
+		return Err("property must return bool or error")
+	}
+	return nil
+}
+
+// propertyCall calls f with args and returns the reason for failure; or "" if the property holds
+func propertyCall(f reflect.Value, args []interface{}) (reason string) {
+	in := make([]reflect.Value, len(args))
+	for i, a := range args {
+		in[i] = reflect.ValueOf(a)
+		if a == nil {
+			in[i] = reflect.Zero(f.Type().In(i))
+		}
+	}
+	var out []reflect.Value
+	value, panicked := capturePanic(func() { out = f.Call(in) })
+	if panicked {
+		return fmt.Sprintf("panic: %v", value)
+	}
+	res := out[0]
+	if res.Kind() == reflect.Bool {
+		if !res.Bool() {
+			return "returned false"
+		}
+		return ""
+	}
+	if !res.IsNil() {
+		return fmt.Sprintf("returned error: %v", res.Interface())
+	}
+	return ""
+}
+
+// propertyShrink simplifies args while the property still fails
+func propertyShrink(f reflect.Value, generators []Generator, args []interface{}, reason string, maxShrinks int) ([]interface{}, string, int) {
+	shrinks, attempts := 0, 0
+	for improved := true; improved && attempts < maxShrinks; {
+		improved = false
+		for i, g := range generators {
+			for _, candidate := range g.Shrink(args[i]) {
+				if attempts++; attempts > maxShrinks {
+					break
+				}
+				trial := append([]interface{}(nil), args...)
+				trial[i] = candidate
+				if r := propertyCall(f, trial); r != "" {
+					args, reason, improved = trial, r, true
+					shrinks++
+					break
+				}
+			}
+		}
+	}
+	return args, reason, shrinks
+}
+
+// propertyFormat formats a list of arguments
+func propertyFormat(args []interface{}) string {
+	items := make([]string, len(args))
+	for i, a := range args {
+		items[i] = fmt.Sprintf("%#v", a)
+	}
+	return strings.Join(items, ", ")
+}
+
+// ------------- generators ------------------
+
+// GenBool returns a generator of bool values
+func GenBool() Generator {
+	return genBool{}
+}
+
+type genBool struct{}
+
+func (o genBool) Type() reflect.Type                  { return reflect.TypeOf(false) }
+func (o genBool) Generate(rnd *rand.Rand) interface{} { return rnd.Intn(2) == 1 }
+
+func (o genBool) Shrink(value interface{}) []interface{} {
+	if value.(bool) {
+		return []interface{}{false}
+	}
+	return nil
+}
+
+// GenInt returns a generator of int values in [min, max]
+//  NOTE: the limits (and zero, if in range) are generated more often; values shrink towards zero
+func GenInt(min, max int) Generator {
+	if min > max {
+		Panic("GenInt: min=%d must not be greater than max=%d", min, max)
+	}
+	return genInt{min, max}
+}
+
+type genInt struct{ min, max int }
+
+func (o genInt) Type() reflect.Type { return reflect.TypeOf(0) }
+
+func (o genInt) Generate(rnd *rand.Rand) interface{} {
+	if rnd.Intn(10) == 0 {
+		edges := []int{o.min, o.max, o.target()}
+		return edges[rnd.Intn(len(edges))]
+	}
+	span := uint64(o.max-o.min) + 1
+	if span == 0 {
+		return int(rnd.Uint64())
+	}
+	return o.min + int(rnd.Uint64()%span)
+}
+
+func (o genInt) Shrink(value interface{}) (res []interface{}) {
+	v, t := value.(int), o.target()
+	if v == t {
+		return
+	}
+	step := 1
+	if v < t {
+		step = -1
+	}
+	for _, c := range []int{t, t + (v-t)/2, v - step} {
+		if c != v && (len(res) == 0 || res[len(res)-1] != c) {
+			res = append(res, c)
+		}
+	}
+	return
+}
+
+// target returns the simplest value: zero clipped to [min, max]
+func (o genInt) target() int {
+	if o.min > 0 {
+		return o.min
+	}
+	if o.max < 0 {
+		return o.max
+	}
+	return 0
+}
+
+// GenFloat64 returns a generator of float64 values in [min, max]
+//  NOTE: the limits (and zero, if in range) are generated more often; values shrink towards zero
+func GenFloat64(min, max float64) Generator {
+	if min > max {
+		Panic("GenFloat64: min=%g must not be greater than max=%g", min, max)
+	}
+	return genFloat64{min, max}
+}
+
+type genFloat64 struct{ min, max float64 }
+
+func (o genFloat64) Type() reflect.Type { return reflect.TypeOf(0.0) }
+
+func (o genFloat64) Generate(rnd *rand.Rand) interface{} {
+	if rnd.Intn(10) == 0 {
+		edges := []float64{o.min, o.max, o.target()}
+		return edges[rnd.Intn(len(edges))]
+	}
+	return o.min + rnd.Float64()*(o.max-o.min)
+}
+
+func (o genFloat64) Shrink(value interface{}) (res []interface{}) {
+	v, t := value.(float64), o.target()
+	if v == t {
+		return
+	}
+	res = append(res, t)
+	if r := math.Trunc(v); r != v && r != t && r >= o.min && r <= o.max {
+		res = append(res, r)
+	}
+	if math.Abs(v-t) > 1e-6 {
+		res = append(res, t+(v-t)/2)
+	}
+	return
+}
+
+// target returns the simplest value: zero clipped to [min, max]
+func (o genFloat64) target() float64 {
+	return math.Max(o.min, math.Min(o.max, 0))
+}
+
+// defaultAlphabet holds the characters used by GenString if no alphabet is given
+const defaultAlphabet = " !\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`abcdefghijklmnopqrstuvwxyz{|}~éç漢"
+
+// GenString returns a generator of strings with up to maxLen characters taken from alphabet
+//  alphabet -- characters to use; "" means printable ASCII plus a few non-ASCII characters
+func GenString(alphabet string, maxLen int) Generator {
+	if maxLen < 0 {
+		Panic("GenString: maxLen=%d must not be negative", maxLen)
+	}
+	if alphabet == "" {
+		alphabet = defaultAlphabet
+	}
+	return genString{[]rune(alphabet), maxLen}
+}
+
+type genString struct {
+	alphabet []rune
+	maxLen   int
+}
+
+func (o genString) Type() reflect.Type { return reflect.TypeOf("") }
+
+func (o genString) Generate(rnd *rand.Rand) interface{} {
+	r := make([]rune, rnd.Intn(o.maxLen+1))
+	for i := range r {
+		r[i] = o.alphabet[rnd.Intn(len(o.alphabet))]
+	}
+	return string(r)
+}
+
+func (o genString) Shrink(value interface{}) (res []interface{}) {
+	r := []rune(value.(string))
+	n := len(r)
+	if n == 0 {
+		return
+	}
+	res = append(res, "")
+	if n > 1 {
+		res = append(res, string(r[:n/2]), string(r[n/2:]))
+	}
+	for i := 0; i < n; i++ {
+		res = append(res, string(r[:i])+string(r[i+1:]))
+	}
+	for i := 0; i < n; i++ {
+		if r[i] != o.alphabet[0] {
+			s := append([]rune(nil), r...)
+			s[i] = o.alphabet[0]
+			res = append(res, string(s))
+		}
+	}
+	return
+}
+
+// GenSlice returns a generator of slices with up to maxLen elements created by elem
+func GenSlice(elem Generator, maxLen int) Generator {
+	if maxLen < 0 {
+		Panic("GenSlice: maxLen=%d must not be negative", maxLen)
+	}
+	return genSlice{elem, maxLen}
+}
+
+type genSlice struct {
+	elem   Generator
+	maxLen int
+}
+
+func (o genSlice) Type() reflect.Type { return reflect.SliceOf(o.elem.Type()) }
+
+func (o genSlice) Generate(rnd *rand.Rand) interface{} {
+	n := rnd.Intn(o.maxLen + 1)
+	s := reflect.MakeSlice(o.Type(), n, n)
+	for i := 0; i < n; i++ {
+		s.Index(i).Set(genValue(o.elem, o.elem.Generate(rnd)))
+	}
+	return s.Interface()
+}
+
+func (o genSlice) Shrink(value interface{}) (res []interface{}) {
+	v := reflect.ValueOf(value)
+	n := v.Len()
+	if n == 0 {
+		return
+	}
+	res = append(res, reflect.MakeSlice(o.Type(), 0, 0).Interface())
+	if n > 1 {
+		res = append(res, o.join(v.Slice(0, n/2)), o.join(v.Slice(n/2, n)))
+	}
+	for i := 0; i < n; i++ {
+		res = append(res, o.join(v.Slice(0, i), v.Slice(i+1, n)))
+	}
+	for i := 0; i < n; i++ {
+		for _, c := range o.elem.Shrink(v.Index(i).Interface()) {
+			s := o.join(v)
+			reflect.ValueOf(s).Index(i).Set(genValue(o.elem, c))
+			res = append(res, s)
+		}
+	}
+	return
+}
+
+// join returns a new slice with the elements of parts
+func (o genSlice) join(parts ...reflect.Value) interface{} {
+	s := reflect.MakeSlice(o.Type(), 0, 0)
+	for _, p := range parts {
+		s = reflect.AppendSlice(s, p)
+	}
+	return s.Interface()
+}
+
+// GenMap returns a generator of maps with up to maxLen entries created by key and val
+//  NOTE: the maps may have fewer entries if key generates repeated values
+func GenMap(key, val Generator, maxLen int) Generator {
+	if maxLen < 0 {
+		Panic("GenMap: maxLen=%d must not be negative", maxLen)
+	}
+	return genMap{key, val, maxLen}
+}
+
+type genMap struct {
+	key, val Generator
+	maxLen   int
+}
+
+func (o genMap) Type() reflect.Type { return reflect.MapOf(o.key.Type(), o.val.Type()) }
+
+func (o genMap) Generate(rnd *rand.Rand) interface{} {
+	n := rnd.Intn(o.maxLen + 1)
+	m := reflect.MakeMapWithSize(o.Type(), n)
+	for i := 0; i < n; i++ {
+		m.SetMapIndex(genValue(o.key, o.key.Generate(rnd)), genValue(o.val, o.val.Generate(rnd)))
+	}
+	return m.Interface()
+}
+
+func (o genMap) Shrink(value interface{}) (res []interface{}) {
+	v := reflect.ValueOf(value)
+	if v.Len() == 0 {
+		return
+	}
+	res = append(res, reflect.MakeMap(o.Type()).Interface())
+	keys := deepMapKeys(v, v)
+	for _, k := range keys {
+		m := o.copy(v)
+		m.SetMapIndex(k, reflect.Value{})
+		res = append(res, m.Interface())
+	}
+	for _, k := range keys {
+		for _, c := range o.val.Shrink(v.MapIndex(k).Interface()) {
+			m := o.copy(v)
+			m.SetMapIndex(k, genValue(o.val, c))
+			res = append(res, m.Interface())
+		}
+	}
+	return
+}
+
+// copy returns a copy of the map v
+func (o genMap) copy(v reflect.Value) reflect.Value {
+	m := reflect.MakeMapWithSize(o.Type(), v.Len())
+	for _, k := range v.MapKeys() {
+		m.SetMapIndex(k, v.MapIndex(k))
+	}
+	return m
+}
+
+// GenStruct returns a generator of structs with the same type as sample
+//  fields -- generators of exported fields; other fields keep the values in sample
+func GenStruct(sample interface{}, fields map[string]Generator) Generator {
+	t := reflect.TypeOf(sample)
+	if t == nil || t.Kind() != reflect.Struct {
+		Panic("GenStruct: sample must be a struct; got %v", t)
+	}
+	names := make([]string, 0, len(fields))
+	for name, g := range fields {
+		f, ok := t.FieldByName(name)
+		if !ok || f.PkgPath != "" {
+			Panic("GenStruct: type %v has no exported field %q", t, name)
+		}
+		if !g.Type().AssignableTo(f.Type) {
+			Panic("GenStruct: generator of field %q creates %v; want %v", name, g.Type(), f.Type)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return genStruct{reflect.ValueOf(sample), names, fields}
+}
+
+type genStruct struct {
+	sample reflect.Value
+	names  []string
+	fields map[string]Generator
+}
+
+func (o genStruct) Type() reflect.Type { return o.sample.Type() }
+
+func (o genStruct) Generate(rnd *rand.Rand) interface{} {
+	s := reflect.New(o.Type()).Elem()
+	s.Set(o.sample)
+	for _, name := range o.names {
+		g := o.fields[name]
+		s.FieldByName(name).Set(genValue(g, g.Generate(rnd)))
+	}
+	return s.Interface()
+}
+
+func (o genStruct) Shrink(value interface{}) (res []interface{}) {
+	v := reflect.ValueOf(value)
+	for _, name := range o.names {
+		g := o.fields[name]
+		for _, c := range g.Shrink(v.FieldByName(name).Interface()) {
+			s := reflect.New(o.Type()).Elem()
+			s.Set(v)
+			s.FieldByName(name).Set(genValue(g, c))
+			res = append(res, s.Interface())
+		}
+	}
+	return
+}
+
+// genValue converts a generated value to reflect.Value (nil becomes the zero value)
+func genValue(g Generator, value interface{}) reflect.Value {
+	if value == nil {
+		return reflect.Zero(g.Type())
+	}
+	return reflect.ValueOf(value)
+}
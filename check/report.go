@@ -0,0 +1,258 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Assertion holds the result of a call to a check function
+type Assertion struct {
+	Test     string        `json:"test"`              // name of the test
+	Msg      string        `json:"msg"`               // message given to the check function
+	Passed   bool          `json:"passed"`            // the check succeeded
+	Duration time.Duration `json:"duration"`          // time elapsed since the previous assertion of the same test
+	Failure  string        `json:"failure,omitempty"` // failure message
+	Caller   Caller        `json:"caller"`            // position of the call to the check function
+}
+
+// recorder holds the assertions recorded since RecordAssertions was called
+var recorder struct {
+	enabled    int32                // 1 if assertions are being recorded (accessed atomically)
+	mu         sync.Mutex           // protects the fields below
+	assertions []Assertion          // recorded assertions
+	last       map[string]time.Time // time of the last assertion of each test
+}
+
+// RecordAssertions starts recording the results of all check functions
+//  NOTE: called by Main when a report is requested
+func RecordAssertions() {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if recorder.last == nil {
+		recorder.last = make(map[string]time.Time)
+	}
+	atomic.StoreInt32(&recorder.enabled, 1)
+}
+
+// ResetAssertions removes the recorded assertions; recording continues if it was enabled
+func ResetAssertions() {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.assertions = nil
+	recorder.last = make(map[string]time.Time)
+}
+
+// Assertions returns a copy of the recorded assertions
+func Assertions() []Assertion {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	return append([]Assertion(nil), recorder.assertions...)
+}
+
+// Main runs the tests and exits; it writes a report of all assertions to the file given by
+// the LOOTBAG_REPORT environment variable, if set
+//
+//   Example:
+//     func TestMain(m *testing.M) {
+//         check.Main(m)
+//     }
+//
+//   and then:
+//     LOOTBAG_REPORT=report.xml go test ./...
+//
+//  NOTE: (1) files ending with ".xml" are written in JUnit XML format; others in JSON
+//        (2) each package has its own test binary, which writes its own file; thus, with
+//            go test ./... and an absolute path, each package overwrites the report of the
+//            previous one; use a relative path (e.g. report.xml) to get one report per package
+//            directory and aggregate them afterwards if needed
+//        (3) an environment variable is used instead of a flag because this package is also
+//            imported by non-test code, whose flag sets must not be changed
+func Main(m *testing.M) {
+	path := os.Getenv("LOOTBAG_REPORT")
+	if path != "" {
+		RecordAssertions()
+	}
+	code := m.Run()
+	if path != "" {
+		if err := WriteReport(path); err != nil {
+			fmt.Fprintf(os.Stderr, "cannot write report: %v\n", err)
+			if code == 0 {
+				code = 1
+			}
+		}
+	}
+	os.Exit(code)
+}
+
+// WriteReport writes the recorded assertions to a file in JUnit XML (if path ends with ".xml")
+// or JSON format
+func WriteReport(path string) (err error) {
+	if err = os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if e := f.Close(); err == nil {
+			err = e
+		}
+	}()
+	if strings.HasSuffix(path, ".xml") {
+		return WriteJUnit(f, reportSuiteName())
+	}
+	return WriteJSONReport(f)
+}
+
+// WriteJSONReport writes the recorded assertions as a JSON array
+func WriteJSONReport(w io.Writer) error {
+	assertions := Assertions()
+	if assertions == nil {
+		assertions = []Assertion{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(assertions)
+}
+
+// junit* define the JUnit XML format
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the recorded assertions in JUnit XML format; each assertion is a test case
+// named after its message and classified by the name of its test
+func WriteJUnit(w io.Writer, suite string) (err error) {
+	s := junitSuite{Name: suite}
+	var total time.Duration
+	for _, a := range Assertions() {
+		c := junitCase{
+			Name:      a.Msg,
+			ClassName: a.Test,
+			Time:      junitSeconds(a.Duration),
+			File:      a.Caller.File,
+			Line:      a.Caller.Line,
+		}
+		if !a.Passed {
+			c.Failure = &junitFailure{Message: strings.SplitN(a.Failure, "\n", 2)[0], Text: a.Failure}
+			s.Failures++
+		}
+		s.Cases = append(s.Cases, c)
+		total += a.Duration
+	}
+	s.Tests = len(s.Cases)
+	s.Time = junitSeconds(total)
+	if _, err = io.WriteString(w, xml.Header); err != nil {
+		return
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err = enc.Encode(junitSuites{Suites: []junitSuite{s}}); err != nil {
+		return
+	}
+	_, err = io.WriteString(w, "\n")
+	return
+}
+
+// junitSeconds formats a duration in seconds
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.6f", d.Seconds())
+}
+
+// reportSuiteName returns the name of the test binary without the ".test" suffix
+func reportSuiteName() string {
+	return strings.TrimSuffix(filepath.Base(os.Args[0]), ".test")
+}
+
+// record stores the result of an assertion if recording is enabled
+func record(tst Reporter, msg string, passed bool, failure string) {
+	if atomic.LoadInt32(&recorder.enabled) == 0 {
+		return
+	}
+	caller := outsideCheck(CaptureStack(1))
+	now := time.Now()
+	name := tst.Name()
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	var duration time.Duration
+	if last, ok := recorder.last[name]; ok {
+		duration = now.Sub(last)
+	}
+	recorder.last[name] = now
+	recorder.assertions = append(recorder.assertions, Assertion{
+		Test:     name,
+		Msg:      msg,
+		Passed:   passed,
+		Duration: duration,
+		Failure:  strings.TrimSuffix(failure, "\n"),
+		Caller:   caller,
+	})
+}
+
+// fail records a failed assertion and reports it with tst.Errorf
+//  NOTE: Soft reporters also receive msg
+func fail(tst Reporter, msg, format string, args ...interface{}) {
+	tst.Helper()
+	text := fmt.Sprintf(format, args...)
+	record(tst, msg, false, text)
+	if s, ok := tst.(*Soft); ok {
+		s.fail(msg, text)
+		return
+	}
+	tst.Errorf("%s", text)
+}
+
+// Fail reports a failed assertion of a check function defined outside this package;
+// like the check functions here, it records the assertion and supports Soft reporters
+func Fail(tst Reporter, msg, format string, args ...interface{}) {
+	tst.Helper()
+	fail(tst, msg, format, args...)
+}
+
+// Pass reports a successful assertion of a check function defined outside this package
+func Pass(tst Reporter, msg string) {
+	printOK(tst, msg)
+}
+
+// passf records a successful assertion and prints a formatted message if the level is LevelVerbose or higher
+func passf(tst Reporter, msg, format string, args ...interface{}) {
+	record(tst, msg, true, "")
+	verbosef(tst, format, args...)
+}
@@ -0,0 +1,156 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Reporter defines the minimal interface used by check functions to report failures
+//  NOTE: testing.TB (e.g. *testing.T and *testing.B) satisfies Reporter
+type Reporter interface {
+	Helper()                                   // marks the calling function as a helper
+	Errorf(format string, args ...interface{}) // records a failure and continues
+	FailNow()                                  // marks the test as failed and stops it
+	Failed() bool                              // returns whether there are failures
+	Name() string                              // returns the name of the test
+}
+
+// Collector is a stand-alone Reporter that collects failures
+// for use outside `go test`; e.g. in a smoke-test binary
+//
+//   Example:
+//     col := check.NewCollector("smoke")
+//     col.Run(func(r check.Reporter) {
+//         check.Int(r, "status", status, 200)
+//     })
+//     if col.Failed() {
+//         fmt.Print(col.Summary())
+//         os.Exit(1)
+//     }
+//
+type Collector struct {
+	name     string     // name of the collection
+	mu       sync.Mutex // protects the fields below
+	failures []string   // failure messages prefixed by the caller position
+	failed   bool       // there are failures
+	cleanups []func()   // functions registered by Cleanup
+}
+
+// collectorStop is the panic value used by Collector.FailNow to stop Run
+type collectorStop struct{}
+
+// NewCollector returns a new Collector
+func NewCollector(name string) (o *Collector) {
+	o = new(Collector)
+	o.name = name
+	return
+}
+
+// Helper does nothing; the caller position is found by skipping the check package
+func (o *Collector) Helper() {}
+
+// Errorf records a failure
+func (o *Collector) Errorf(format string, args ...interface{}) {
+	text := strings.TrimSuffix(fmt.Sprintf(format, args...), "\n")
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.failures = append(o.failures, collectorCaller()+": "+text)
+	o.failed = true
+}
+
+// FailNow marks the collection as failed and stops the function given to Run
+func (o *Collector) FailNow() {
+	o.mu.Lock()
+	o.failed = true
+	o.mu.Unlock()
+	panic(collectorStop{})
+}
+
+// Failed returns whether there are failures
+func (o *Collector) Failed() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.failed
+}
+
+// Name returns the name of the collection
+func (o *Collector) Name() string {
+	return o.name
+}
+
+// Cleanup registers a function to be called when Run returns
+//  NOTE: functions are called in last-in-first-out order
+func (o *Collector) Cleanup(fn func()) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.cleanups = append(o.cleanups, fn)
+}
+
+// Run calls fn with this Collector and returns true if no failures were recorded
+//  NOTE: (1) a call to FailNow within fn stops fn; other panics are propagated
+//        (2) functions registered with Cleanup are called after fn returns
+func (o *Collector) Run(fn func(r Reporter)) (ok bool) {
+	func() {
+		defer o.runCleanups()
+		defer func() {
+			if err := recover(); err != nil {
+				if _, stop := err.(collectorStop); !stop {
+					panic(err)
+				}
+			}
+		}()
+		fn(o)
+	}()
+	return !o.Failed()
+}
+
+// runCleanups calls and removes the functions registered by Cleanup
+func (o *Collector) runCleanups() {
+	for {
+		o.mu.Lock()
+		n := len(o.cleanups)
+		if n == 0 {
+			o.mu.Unlock()
+			return
+		}
+		fn := o.cleanups[n-1]
+		o.cleanups = o.cleanups[:n-1]
+		o.mu.Unlock()
+		fn()
+	}
+}
+
+// Failures returns a copy of the failure messages
+func (o *Collector) Failures() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]string(nil), o.failures...)
+}
+
+// Summary returns a report with all failures
+func (o *Collector) Summary() string {
+	failures := o.Failures()
+	if len(failures) == 0 {
+		if o.Failed() {
+			return fmt.Sprintf("--- FAIL: %s\n", o.name)
+		}
+		return fmt.Sprintf("--- PASS: %s\n", o.name)
+	}
+	l := fmt.Sprintf("--- FAIL: %s (%d failures)\n", o.name, len(failures))
+	for _, f := range failures {
+		l += "    " + strings.Replace(f, "\n", "\n        ", -1) + "\n"
+	}
+	return l
+}
+
+// collectorCaller returns the position of the first caller outside the check package
+func collectorCaller() string {
+	c := outsideCheck(CaptureStack(2))
+	return fmt.Sprintf("%s:%d", filepath.Base(c.File), c.Line)
+}
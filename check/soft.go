@@ -0,0 +1,129 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// softMaxShown is the default maximum number of failures shown by Soft.Report
+const softMaxShown = 10
+
+// Soft is a Reporter that accumulates failures and reports them together at the end of a test
+//
+//   Example:
+//     func TestHandler(tst *testing.T) {
+//         soft := check.NewSoft(tst)
+//         check.Int(soft, "status", status, 200)
+//         check.JSON(soft, "body", body, `{"ok":true}`)
+//         check.String(soft, "content type", ctype, "application/json")
+//     } // all failures are reported here, as a numbered summary
+//
+//  NOTE: (1) FailNow does not stop the test; it only marks it as failed, thus the remaining
+//            assertions run (e.g. after RecoverTst)
+//        (2) the summary is reported by Report, which is registered with tst.Cleanup if tst
+//            has a Cleanup method (e.g. testing.TB or Collector); otherwise, defer soft.Report()
+type Soft struct {
+	MaxShown int // maximum number of failures shown by Report; the others are only counted
+
+	parent   Reporter      // reporter of the test
+	mu       sync.Mutex    // protects the fields below
+	failures []softFailure // failures not reported yet
+	failed   bool          // there are failures (including reported ones) or FailNow was called
+}
+
+// softFailure holds a failure collected by Soft
+type softFailure struct {
+	caller string // "file:line" of the assertion
+	msg    string // message given to the check function [may be empty]
+	text   string // failure message
+}
+
+// NewSoft returns a new Soft reporting to tst
+func NewSoft(tst Reporter) (o *Soft) {
+	o = new(Soft)
+	o.MaxShown = softMaxShown
+	o.parent = tst
+	if c, ok := tst.(interface{ Cleanup(func()) }); ok {
+		c.Cleanup(o.Report)
+	}
+	return
+}
+
+// Helper does nothing; the caller position is found by skipping the check package
+func (o *Soft) Helper() {}
+
+// Errorf collects a failure
+func (o *Soft) Errorf(format string, args ...interface{}) {
+	o.fail("", fmt.Sprintf(format, args...))
+}
+
+// FailNow marks the test as failed without stopping it
+func (o *Soft) FailNow() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.failed = true
+}
+
+// Failed returns whether there are failures in this collector or in the test
+func (o *Soft) Failed() bool {
+	o.mu.Lock()
+	failed := o.failed
+	o.mu.Unlock()
+	return failed || o.parent.Failed()
+}
+
+// Name returns the name of the test
+func (o *Soft) Name() string {
+	return o.parent.Name()
+}
+
+// Report reports the collected failures to the test as a numbered summary
+//  NOTE: failures are reported only once; calling Report again reports only new failures
+func (o *Soft) Report() {
+	o.parent.Helper()
+	o.mu.Lock()
+	failures := o.failures
+	o.failures = nil
+	failed := o.failed
+	o.mu.Unlock()
+	if len(failures) == 0 {
+		if failed && !o.parent.Failed() {
+			o.parent.Errorf("FailNow called by a soft assertion\n")
+		}
+		return
+	}
+	o.parent.Errorf("%s", softSummary(failures, o.MaxShown))
+}
+
+// fail collects a failure of the check function with message msg
+func (o *Soft) fail(msg, text string) {
+	caller := collectorCaller()
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.failures = append(o.failures, softFailure{caller, msg, strings.Trim(text, "\n")})
+	o.failed = true
+}
+
+// softSummary formats failures, showing at most maxShown of them
+func softSummary(failures []softFailure, maxShown int) string {
+	l := fmt.Sprintf("%d soft assertions failed:\n", len(failures))
+	for i, f := range failures {
+		if maxShown > 0 && i == maxShown {
+			l += fmt.Sprintf("  ... and %d more\n", len(failures)-maxShown)
+			break
+		}
+		label := fmt.Sprintf("%3d) ", i+1)
+		indent := strings.Repeat(" ", len(label))
+		head := f.caller
+		if f.msg != "" {
+			head += ": " + f.msg
+		}
+		l += label + head + "\n" + indent + strings.Replace(f.text, "\n", "\n"+indent, -1) + "\n"
+	}
+	return l
+}
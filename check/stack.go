@@ -0,0 +1,96 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// maxStackDepth is the maximum number of frames captured by CaptureStack
+const maxStackDepth = 64
+
+// checkPrefix is the prefix of the functions in this package
+const checkPrefix = "github.com/cpmech/lootbag/check."
+
+// StackFilters holds prefixes of functions hidden by FormatStack
+//  NOTE: functions of this package are always hidden, except for tests
+var StackFilters = []string{
+	"runtime.",
+	"testing.",
+	"net/http.",
+	"github.com/go-chi/chi",
+}
+
+// CaptureStack returns the stack of callers
+//  skip -- number of frames to skip; use skip=0 to start at the caller of CaptureStack
+func CaptureStack(skip int) (stack []Caller) {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(skip+2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, Caller{File: frame.File, Line: frame.Line, Func: frame.Function})
+		if !more {
+			return
+		}
+	}
+}
+
+// FormatStack returns a compact representation of the stack, one "file:line func" per line
+//  NOTE: frames matching StackFilters (and frames of this package) are hidden
+func FormatStack(stack []Caller) (l string) {
+	for _, c := range stack {
+		if stackHidden(c) {
+			continue
+		}
+		l += fmt.Sprintf("    %s:%d %s\n", c.File, c.Line, shortFuncName(c.Func))
+	}
+	return
+}
+
+// stackHidden returns true if the frame should not be shown
+func stackHidden(c Caller) bool {
+	if inCheck(c) {
+		return true
+	}
+	for _, prefix := range StackFilters {
+		if strings.HasPrefix(c.Func, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// inCheck returns true if the frame belongs to the non-test code of this package
+func inCheck(c Caller) bool {
+	return strings.HasPrefix(c.Func, checkPrefix) && !strings.HasSuffix(c.File, "_test.go")
+}
+
+// outsideCheck returns the first frame that does not belong to this package
+func outsideCheck(stack []Caller) Caller {
+	for _, c := range stack {
+		if !inCheck(c) {
+			return c
+		}
+	}
+	return Caller{File: "?"}
+}
+
+// shortFuncName removes the path of the package from a function name
+//   Example: "github.com/cpmech/lootbag/lio.Atoi" => "lio.Atoi"
+func shortFuncName(name string) string {
+	return name[strings.LastIndex(name, "/")+1:]
+}
+
+// panicStack returns the stack attached to a panic value or, if there is none,
+// the current stack (which includes the panicking frames when called from a deferred function)
+func panicStack(err interface{}) []Caller {
+	if e, ok := err.(*Error); ok && len(e.Stack) > 0 {
+		return e.Stack
+	}
+	return CaptureStack(2)
+}
@@ -0,0 +1,112 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBench01(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Bench01. record and compare baselines")
+
+	dir, err := ioutil.TempDir("", "lootbag-bench")
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "bench.json")
+
+	// record
+	bl := NewBenchBaseline(path, 20)
+	bl.Check(tst, "handler", testing.BenchmarkResult{N: 1000, T: time.Millisecond, MemAllocs: 2000, MemBytes: 64000})
+	res, ok := bl.Baseline("handler")
+	if !ok {
+		tst.Errorf("baseline should have been recorded\n")
+		return
+	}
+	Deep(tst, "recorded", res, BenchResult{NsPerOp: 1000, BytesPerOp: 64, AllocsPerOp: 2})
+
+	// load and compare
+	bl = NewBenchBaseline(path, 20)
+	bl.Check(tst, "handler", testing.BenchmarkResult{N: 1000, T: 1100 * time.Microsecond, MemAllocs: 2000, MemBytes: 64000})
+
+	col := NewCollector("bench")
+	col.Run(func(r Reporter) {
+		bl.Check(r, "handler", testing.BenchmarkResult{N: 1000, T: 1500 * time.Microsecond, MemAllocs: 3000, MemBytes: 64000})
+	})
+	failures := col.Failures()
+	Int(tst, "number of failures", len(failures), 1)
+	if !strings.Contains(failures[0], "ns/op: 1000 => 1500 (+50.0% > 20%)") || !strings.Contains(failures[0], "allocs/op: 2 => 3") {
+		tst.Errorf("failure message is incorrect:\n%s", failures[0])
+	}
+
+	// warn only
+	bl.WarnOnly = true
+	bl.Check(tst, "handler", testing.BenchmarkResult{N: 1000, T: 2 * time.Millisecond, MemAllocs: 2000, MemBytes: 64000})
+}
+
+func TestBench02(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Bench02. regression and invalid baselines")
+
+	Float64(tst, "no regression", 1e-15, regression(100, 90), 0)
+	Float64(tst, "10%", 1e-15, regression(100, 110), 10)
+	if !math.IsInf(regression(0, 1), 1) {
+		tst.Errorf("regression from zero should be infinite\n")
+	}
+
+	dir, err := ioutil.TempDir("", "lootbag-bench")
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	if err = ioutil.WriteFile(filepath.Join(dir, "invalid.json"), []byte("{"), 0644); err != nil {
+		tst.Fatalf("cannot write file: %v\n", err)
+	}
+	PanicsWith(tst, "invalid file", "cannot parse benchmark baselines", func() {
+		NewBenchBaseline(filepath.Join(dir, "invalid.json"), 10)
+	})
+}
+
+func TestBench03(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Bench03. run benchmark")
+
+	// running a benchmark takes about one second
+	if os.Getenv("LOOTBAG_BENCH") == "" {
+		tst.Skip("set LOOTBAG_BENCH=1 to run benchmarks")
+	}
+
+	dir, err := ioutil.TempDir("", "lootbag-bench")
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	bl := NewBenchBaseline(filepath.Join(dir, "bench.json"), 1000)
+	bl.Run(tst, "sum", func(b *testing.B) {
+		s := 0
+		for i := 0; i < b.N; i++ {
+			s += i
+		}
+	})
+	if _, ok := bl.Baseline("sum"); !ok {
+		tst.Errorf("baseline should have been recorded\n")
+	}
+}
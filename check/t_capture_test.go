@@ -0,0 +1,64 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCapture01(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Capture01. stdout, stderr and log")
+
+	stdout, stderr, logs := CaptureOutput(func() {
+		fmt.Printf("hello stdout\n")
+		fmt.Fprintf(os.Stderr, "hello stderr\n")
+		log.Printf("hello log")
+	})
+	String(tst, "stdout", stdout, "hello stdout\n")
+	String(tst, "stderr", stderr, "hello stderr\n")
+	if !strings.HasSuffix(logs, "hello log\n") {
+		tst.Errorf("logs is incorrect: %q\n", logs)
+	}
+
+	stdout, _, _ = CaptureOutput(func() {})
+	String(tst, "nothing", stdout, "")
+
+	out := os.Stdout
+	Panics(tst, "restored after panic", func() {
+		CaptureOutput(func() { panic("stop") })
+	})
+	if os.Stdout != out {
+		tst.Errorf("os.Stdout should have been restored\n")
+	}
+}
+
+func TestCapture02(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Capture02. verbose output")
+
+	SetTestVerbosity(tst, LevelNormal)
+	stdout, _, _ := CaptureOutput(func() { Int(tst, "1 == 1", 1, 1) })
+	String(tst, "quiet", stdout, "")
+
+	defer atomic.StoreInt32(&globalLevel, atomic.LoadInt32(&globalLevel))
+	SetVerbosity(LevelVerbose)
+	SetTestVerbosity(tst, LevelVerbose)
+	stdout, _, logs := CaptureOutput(func() {
+		Int(tst, "1 == 1", 1, 1)
+		CallerInfo(1)
+	})
+	String(tst, "verbose", stdout, "1 == 1: OK\n")
+	if !strings.Contains(logs, "t_capture_test.go:") || !strings.Contains(logs, "func = github.com/cpmech/lootbag/check.TestCapture02") {
+		tst.Errorf("logs is incorrect: %q\n", logs)
+	}
+}
@@ -6,6 +6,7 @@ package check
 
 import (
 	"math"
+	"strings"
 	"testing"
 	"time"
 )
@@ -154,3 +155,47 @@ func TestCheck06(tst *testing.T) {
 		tst.Errorf("test should not have failed")
 	}
 }
+
+func TestCheck07(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Check07. Strings, Ints and Int64s")
+
+	Strings(tst, "strings", []string{"a", "b"}, []string{"a", "b"})
+	Ints(tst, "ints", []int{1, 2}, []int{1, 2})
+	Int64s(tst, "int64s", []int64{1, 2}, []int64{1, 2})
+
+	col := NewCollector("slices")
+	Strings(col, "order", []string{"a", "b"}, []string{"b", "a"})
+	Ints(col, "length", []int{1, 2}, []int{1})
+	Int64s(col, "values", []int64{1, 2}, []int64{1, 3})
+	failures := col.Failures()
+	Int(tst, "number of failures", len(failures), 3)
+	if len(failures) == 3 {
+		for i, correct := range []string{`a[0]="a" != b[0]="b"`, "len(a)=2 != len(b)=1", "a[1]=2 != b[1]=3"} {
+			String(tst, "failure", failures[i][strings.Index(failures[i], ": ")+2:], correct)
+		}
+	}
+}
+
+func TestCheck08(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Check08. ElementsMatch")
+
+	ElementsMatch(tst, "strings", []string{"b", "a", "b"}, []string{"a", "b", "b"})
+	ElementsMatch(tst, "array and slice", [3]int{3, 2, 1}, []int{1, 2, 3})
+	ElementsMatch(tst, "structs", []deepUser{{Name: "B"}, {Name: "A"}}, []deepUser{{Name: "A"}, {Name: "B"}})
+	ElementsMatch(tst, "empty", []string{}, nil)
+
+	col := NewCollector("elements")
+	ElementsMatch(col, "multiplicity", []string{"a", "a", "b", "x"}, []string{"a", "b", "b", "c", "c"})
+	ElementsMatch(col, "not a slice", 1, []int{1})
+	failures := col.Failures()
+	Int(tst, "number of failures", len(failures), 2)
+	if len(failures) == 2 {
+		res := failures[0][strings.Index(failures[0], ": ")+2:]
+		String(tst, "failure", res, "missing: \"b\", \"c\" (×2)\nextra: \"a\", \"x\"")
+		String(tst, "failure", failures[1][strings.Index(failures[1], ": ")+2:], "value of type int is not a slice or array")
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"testing"
+	"time"
+)
+
+var clockStart = time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// fired returns whether c has received a value
+func fired(c <-chan time.Time) bool {
+	select {
+	case <-c:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestClock01(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Clock01. FakeClock timers")
+
+	clock := NewFakeClock(clockStart)
+	timer := clock.NewTimer(time.Minute)
+	after := clock.After(2 * time.Minute)
+	Int(tst, "pending", clock.Pending(), 2)
+
+	clock.Advance(59 * time.Second)
+	Bools(tst, "timer before", []bool{fired(timer.C())}, []bool{false})
+	clock.Advance(time.Second)
+	Bools(tst, "timer", []bool{fired(timer.C())}, []bool{true})
+	Bools(tst, "after before", []bool{fired(after)}, []bool{false})
+	Bools(tst, "stop fired timer", []bool{timer.Stop()}, []bool{false})
+
+	Bools(tst, "reset", []bool{timer.Reset(time.Hour)}, []bool{false})
+	clock.Set(clockStart.Add(3 * time.Minute))
+	Bools(tst, "after", []bool{fired(after)}, []bool{true})
+	Bools(tst, "stop active timer", []bool{timer.Stop()}, []bool{true})
+	clock.Advance(2 * time.Hour)
+	Bools(tst, "stopped timer", []bool{fired(timer.C())}, []bool{false})
+
+	Time(tst, "now", clock.Now(), clockStart.Add(2*time.Hour+3*time.Minute))
+	clock.Set(clockStart)
+	Time(tst, "never backwards", clock.Now(), clockStart.Add(2*time.Hour+3*time.Minute))
+	Int(tst, "pending", clock.Pending(), 0)
+}
+
+func TestClock02(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Clock02. FakeClock tickers")
+
+	clock := NewFakeClock(clockStart)
+	ticker := clock.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	var ticks []time.Time
+	for i := 0; i < 3; i++ {
+		clock.Advance(10 * time.Second)
+		ticks = append(ticks, <-ticker.C())
+	}
+	for i, t := range ticks {
+		Time(tst, "tick", t, clockStart.Add(time.Duration(i+1)*10*time.Second))
+	}
+
+	// dropped ticks
+	clock.Advance(time.Minute)
+	Time(tst, "first tick kept", <-ticker.C(), clockStart.Add(40*time.Second))
+	Bools(tst, "others dropped", []bool{fired(ticker.C())}, []bool{false})
+
+	Panics(tst, "zero interval", func() { clock.NewTicker(0) })
+}
+
+func TestClock03(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Clock03. WaitForClock and TimeNear")
+
+	clock := NewFakeClock(clockStart)
+	start := time.Now()
+	attempts, err := WaitForClock(clock, time.Hour, time.Second, func() error {
+		return Err("never ready")
+	})
+	if err == nil {
+		tst.Errorf("WaitForClock should have failed\n")
+	}
+	if time.Since(start) > time.Second {
+		tst.Errorf("WaitForClock with a fake clock should not sleep\n")
+	}
+	Time(tst, "after timeout", clock.Now(), clockStart.Add(time.Hour))
+	Int(tst, "attempts", attempts, 230) // sleeps of 1, 2, 4 and 8 s, 224 of 16 s and a last one of 1 s
+
+	attempts, _ = WaitForClock(NewFakeClock(clockStart), 10*time.Millisecond, 0, func() error {
+		return Err("never ready")
+	})
+	Int(tst, "attempts with zero interval", attempts, 5) // sleeps of 1, 2, 4 and 3 ms
+
+	Int64(tst, "since", int64(clock.Since(clockStart)), int64(time.Hour))
+	Int64(tst, "real since", int64(RealClock.Since(RealClock.Now().Add(-time.Minute)).Round(time.Minute)), int64(time.Minute))
+
+	TimeNear(tst, "near", clockStart, clockStart.Add(time.Second), time.Second)
+	TimeNear(tst, "near", clockStart.Add(time.Second), clockStart, time.Second)
+	col := NewCollector("far")
+	TimeNear(col, "far", clockStart, clockStart.Add(time.Second+1), time.Second)
+	if !col.Failed() {
+		tst.Errorf("TimeNear should have failed\n")
+	}
+	col = NewCollector("saturated")
+	TimeNear(col, "zero time", time.Time{}, clockStart, time.Second)
+	TimeNear(col, "zero time", clockStart, time.Time{}, time.Second)
+	Int(tst, "saturated differences", len(col.Failures()), 2)
+}
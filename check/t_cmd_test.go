@@ -0,0 +1,78 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package check
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCmd01(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Cmd01. output, exit code, env, stdin and dir")
+
+	res := Cmd(tst, "sh", "-c", "echo hello; echo oops >&2; exit 3")
+	res.Exit(3).OutIs("hello\n").ErrIs("oops\n")
+	String(tst, "line", res.Line, "sh -c echo hello; echo oops >&2; exit 3")
+	if res.Duration <= 0 || res.TimedOut {
+		tst.Errorf("duration should be positive and the command should not time out\n")
+	}
+
+	dir := TempTree(tst, map[string]string{"data.txt": "x"})
+	opts := CmdOptions{
+		Timeout: 10 * time.Second,
+		Env:     []string{"LOOTBAG_CMD=value"},
+		Stdin:   "line 1\nline 2\n",
+		Dir:     dir,
+	}
+	CmdWith(tst, opts, "sh", "-c", "echo $LOOTBAG_CMD; wc -l; ls").
+		Success().
+		OutThat(AllOf(HasPrefix("value\n"), Regex(`(?m)^\s*2$`), Contains("data.txt"))).
+		ErrThat(Empty())
+}
+
+func TestCmd02(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Cmd02. failures and timeout")
+
+	col := NewCollector("cmd")
+	res := Cmd(col, "lootbag-command-that-does-not-exist")
+	Int(tst, "exit code of missing command", res.ExitCode, -1)
+
+	Cmd(col, "sh", "-c", "echo bad >&2; exit 1").Success().OutThat(Contains("good"))
+
+	// the subprocess (sleep) must be killed as well; otherwise the output pipe would stay open
+	start := time.Now()
+	res = CmdWith(col, CmdOptions{Timeout: 100 * time.Millisecond}, "sh", "-c", "echo started; sleep 10 & sleep 10")
+	if time.Since(start) > 5*time.Second {
+		tst.Errorf("process group should have been killed after the timeout\n")
+	}
+	Bools(tst, "timed out", []bool{res.TimedOut}, []bool{true})
+	Int(tst, "exit code of killed command", res.ExitCode, -1)
+	String(tst, "output before timeout", res.Stdout, "started\n")
+
+	failures := col.Failures()
+	Int(tst, "number of failures", len(failures), 4)
+	if len(failures) != 4 {
+		return
+	}
+	for i, prefix := range []string{
+		"cannot start command: ",
+		"exit code 1 != 0\nstderr:\nbad\n",
+		"value: \"\"\nexpected: contains \"good\"",
+		"command killed after timeout of 100ms\nstdout:\nstarted\n",
+	} {
+		res := failures[i][strings.Index(failures[i], ": ")+2:]
+		if !strings.HasPrefix(res, prefix) {
+			tst.Errorf("failure %d should start with %q:\n%s\n", i, prefix, res)
+		}
+	}
+}
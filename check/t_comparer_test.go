@@ -0,0 +1,117 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+)
+
+type cmpMoney struct {
+	Cents    int64
+	Currency string
+}
+
+type cmpOrder struct {
+	ID       string
+	Total    cmpMoney
+	Weight   float64
+	Created  time.Time
+	Tags     []string
+	Items    map[string]int
+	Template *template.Template
+	cache    []byte
+}
+
+func TestComparer01(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Comparer01. registered comparers")
+
+	utc := time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+	local := utc.In(time.FixedZone("BRT", -3*3600))
+	a := cmpOrder{ID: "1", Created: utc, Template: template.Must(template.New("t").Parse("{{.}}"))}
+	b := cmpOrder{ID: "1", Created: local, Template: template.Must(template.New("t").Parse("{{.}}"))}
+
+	if len(DeepDiff(a, b)) == 0 {
+		tst.Errorf("times with different locations and templates should differ by default\n")
+	}
+
+	unregisterTime := RegisterComparer(func(x, y time.Time) bool { return x.Equal(y) })
+	unregisterTemplate := RegisterComparer(func(x, y *template.Template) bool { return x.Name() == y.Name() })
+	Deep(tst, "with comparers", a, b)
+
+	b.Created = b.Created.Add(time.Second)
+	String(tst, "time differs", strings.Join(DeepDiff(a, b), "\n"), ".Created: "+a.Created.String()+" != "+b.Created.String())
+
+	// per-call comparers take precedence
+	Deep(tst, "option", a, b, Comparer(func(x, y time.Time) bool { return true }))
+
+	unregisterTime()
+	unregisterTemplate()
+	b.Created = local
+	if len(DeepDiff(a, b)) == 0 {
+		tst.Errorf("comparers should have been removed\n")
+	}
+
+	Panics(tst, "invalid comparer", func() { RegisterComparer(func(x int, y string) bool { return true }) })
+	Panics(tst, "nil comparer", func() { Comparer(nil) })
+}
+
+func TestComparer02(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Comparer02. options")
+
+	a := cmpOrder{ID: "1", Total: cmpMoney{100, "USD"}, Weight: 1.0, Tags: []string{"b", "a"}, cache: []byte("x")}
+	b := cmpOrder{ID: "2", Total: cmpMoney{100, "USD"}, Weight: 1.0 + 1e-12, Tags: []string{"a", "b"}, Items: map[string]int{}}
+
+	correct := strings.Join([]string{
+		`.ID: "1" != "2"`,
+		".Weight: 1 != 1.000000000001",
+		`.Tags[0]: "b" != "a"`,
+		`.Tags[1]: "a" != "b"`,
+		".Items: <nil> != map[]",
+		".cache: [120] != <nil>",
+	}, "\n")
+	String(tst, "no options", strings.Join(DeepDiff(a, b), "\n"), correct)
+
+	Deep(tst, "all options", a, b,
+		IgnoreFields(cmpOrder{}, "ID"),
+		IgnoreUnexported(),
+		EquateApprox(AbsTol(1e-10)),
+		EquateEmpty(),
+		SortSlices(func(x, y string) bool { return x < y }),
+	)
+
+	// tolerance is reported
+	b.Weight = 1.1
+	res := strings.Join(DeepDiff(a, b, EquateApprox(AbsTol(1e-10)), IgnoreFields(&cmpOrder{}, "ID", "Tags", "Items", "cache")), "\n")
+	String(tst, "approx", res, ".Weight: 1 != 1.1 (error = 0.10000000000000009; absolute tolerance 1e-10)")
+
+	// infinities and NaN
+	inf, nan := math.Inf(1), math.NaN()
+	Int(tst, "equal infinities", len(DeepDiff([]float64{inf, -inf}, []float64{inf, -inf}, EquateApprox(AbsTol(1e-10)))), 0)
+	Int(tst, "different infinities", len(DeepDiff(inf, -inf, EquateApprox(AbsTol(1e-10)))), 1)
+	Int(tst, "NaN", len(DeepDiff(nan, nan, EquateApprox(AbsTol(1e-10)))), 1)
+
+	// promoted fields
+	type inner struct{ X, Y int }
+	type outer struct {
+		inner
+		Z int
+	}
+	type outerPtr struct{ *inner }
+	res = strings.Join(DeepDiff(outer{inner{1, 2}, 3}, outer{inner{9, 8}, 3}, IgnoreFields(outer{}, "X")), "\n")
+	String(tst, "promoted field", res, ".inner.Y: 2 != 8")
+	res = strings.Join(DeepDiff(outerPtr{&inner{1, 2}}, outerPtr{&inner{9, 2}}, IgnoreFields(outerPtr{}, "X")), "\n")
+	String(tst, "promoted field of pointer", res, "")
+
+	Panics(tst, "unknown field", func() { IgnoreFields(cmpOrder{}, "Missing") })
+	Panics(tst, "not a struct", func() { IgnoreFields(1, "ID") })
+}
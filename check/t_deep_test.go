@@ -0,0 +1,123 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"strings"
+	"testing"
+)
+
+type deepUser struct {
+	Name  string
+	Email string
+	Tags  []string
+}
+
+type deepGroup struct {
+	Users []*deepUser
+	Meta  map[string]interface{}
+	next  *deepGroup
+}
+
+func TestDeep01(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Deep01. structs, maps and slices")
+
+	a := &deepGroup{
+		Users: []*deepUser{{"A", "a@a.com", []string{"x"}}, {"B", "b@b.com", nil}},
+		Meta:  map[string]interface{}{"n": 1, "s": "hello"},
+	}
+	b := &deepGroup{
+		Users: []*deepUser{{"A", "a@a.com", []string{"x"}}, {"B", "b@b.com", nil}},
+		Meta:  map[string]interface{}{"n": 1, "s": "hello"},
+	}
+
+	r := new(testing.T)
+	Deep(r, "a == b", a, b)
+	if r.Failed() {
+		tst.Errorf("test should not have failed")
+	}
+
+	b.Users[1].Email = "c@c.com"
+	b.Meta["s"] = "world"
+	t := new(testing.T)
+	Deep(t, "a != b", a, b)
+	if !t.Failed() {
+		tst.Errorf("test should have failed")
+	}
+}
+
+func TestDeep02(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Deep02. DeepDiff paths")
+
+	a := deepGroup{
+		Users: []*deepUser{{"A", "a", []string{"x", "y"}}, {"B", "b", nil}},
+		Meta:  map[string]interface{}{"n": 1, "s": "hello", "only-a": true},
+	}
+	b := deepGroup{
+		Users: []*deepUser{{"A", "b", []string{"x"}}, {"B", "b", []string{}}, nil},
+		Meta:  map[string]interface{}{"n": 1.0, "s": "world", "only-b": false},
+	}
+
+	res := strings.Join(DeepDiff(a, b), "\n")
+	correct := strings.Join([]string{
+		`.Users[0].Email: "a" != "b"`,
+		`.Users[0].Tags[1]: "y" != <missing>`,
+		`.Users[1].Tags: <nil> != []`,
+		`.Users[2]: <missing> != <nil>`,
+		`.Meta["n"]: type int != type float64`,
+		`.Meta["only-a"]: true != <missing>`,
+		`.Meta["only-b"]: <missing> != false`,
+		`.Meta["s"]: "hello" != "world"`,
+	}, "\n")
+	String(tst, "diffs", res, correct)
+
+	String(tst, "root", strings.Join(DeepDiff(1, 2), "\n"), "1 != 2")
+	String(tst, "nil", strings.Join(DeepDiff(nil, 2), "\n"), "<nil> != 2")
+	Int(tst, "nil == nil", len(DeepDiff(nil, nil)), 0)
+}
+
+func TestDeep03(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Deep03. cycles and unexported fields")
+
+	a := &deepGroup{next: &deepGroup{Meta: map[string]interface{}{"k": 1}}}
+	a.next.next = a
+	b := &deepGroup{next: &deepGroup{Meta: map[string]interface{}{"k": 1}}}
+	b.next.next = b
+
+	Int(tst, "equal cycles", len(DeepDiff(a, b)), 0)
+
+	b.next.Meta["k"] = 2
+	String(tst, "unexported", strings.Join(DeepDiff(a, b), "\n"), `.next.Meta["k"]: 1 != 2`)
+
+	f := func() {}
+	String(tst, "func", strings.Join(DeepDiff(f, f), "\n"), "functions can only be compared when both are nil")
+}
+
+func TestDeep04(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Deep04. distinct map keys with the same text")
+
+	a := map[interface{}]int{1: 1, 1.0: 2}
+	b := map[interface{}]int{1: 1}
+	String(tst, "keys", strings.Join(DeepDiff(a, b), "\n"), "[1]: 2 != <missing>")
+	String(tst, "keys swapped", strings.Join(DeepDiff(b, a), "\n"), "[1]: <missing> != 2")
+}
+
+func TestDeep05(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Deep05. slices sharing the same backing array")
+
+	type pair struct{ X, Y []int }
+	s, u := []int{1, 2, 3}, []int{1, 2, 4}
+	String(tst, "sub-slices", strings.Join(DeepDiff(pair{s[:2], s[:3]}, pair{u[:2], u[:3]}), "\n"), ".Y[2]: 3 != 4")
+}
@@ -0,0 +1,88 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiff01(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Diff01. unified diff")
+
+	String(tst, "equal", Diff("a\nb\n", "a\nb\n"), "")
+
+	a := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+	b := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neighty\nnine\nten\neleven\n"
+	opts := DiffOptions{Context: 1}
+	String(tst, "diff", DiffWith(a, b, opts), `--- a
++++ b
+@@ -7,5 +7,6 @@
+ seven
+-eight
++eighty
+?     ^
+ nine
+ ten
++eleven
+ 
+`)
+
+	b = "zero\n" + a
+	String(tst, "insertion", DiffWith(a, b, opts), `--- a
++++ b
+@@ -1,1 +1,2 @@
++zero
+ one
+`)
+}
+
+func TestDiff02(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Diff02. options")
+
+	opts := DiffOptions{Context: 0, Color: true}
+	String(tst, "color", DiffWith("x\na", "x\nb", opts), strings.Join([]string{
+		ansiRed + "--- a" + ansiReset,
+		ansiGreen + "+++ b" + ansiReset,
+		ansiCyan + "@@ -2,1 +2,1 @@" + ansiReset,
+		ansiRed + "-a" + ansiReset,
+		ansiGreen + "+b" + ansiReset,
+		"?^",
+	}, "\n")+"\n")
+
+	// empty ranges start at the line before them
+	opts = DiffOptions{Context: 0}
+	String(tst, "insertion", DiffWith("x", "x\ny", opts), "--- a\n+++ b\n@@ -1,0 +2,1 @@\n+y\n")
+	String(tst, "deletion", DiffWith("x\ny", "x", opts), "--- a\n+++ b\n@@ -2,1 +1,0 @@\n-y\n")
+	String(tst, "at the beginning", DiffWith("x", "y\nx", opts), "--- a\n+++ b\n@@ -0,0 +1,1 @@\n+y\n")
+
+	var la, lb []string
+	for i := 0; i < 100; i++ {
+		la = append(la, "a")
+		lb = append(lb, "b")
+	}
+	opts = DiffOptions{MaxLines: 5}
+	res := strings.Split(DiffWith(strings.Join(la, "\n"), strings.Join(lb, "\n"), opts), "\n")
+	Int(tst, "truncated", len(res), 7)
+	String(tst, "last line", res[5], "... (298 more lines)")
+
+	Int(tst, "divergence", divergence("héllo", "hélp"), 3)
+}
+
+func TestDiff03(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Diff03. String with multiple lines")
+
+	t := new(testing.T)
+	String(t, "multi-line", "<p>\nhello\n</p>", "<p>\nworld\n</p>")
+	if !t.Failed() {
+		tst.Errorf("test should have failed")
+	}
+}
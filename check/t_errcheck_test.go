@@ -0,0 +1,99 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// errLines removes the line numbers from error chains
+var errLines = regexp.MustCompile(`\.go:\d+`)
+
+// errPathType matches the type of os.PathError, which is an alias of fs.PathError since Go 1.16
+var errPathType = regexp.MustCompile(`\*(fs|os)\.PathError`)
+
+// errNormalize removes line numbers and the package of os.PathError from error chains
+func errNormalize(s string) string {
+	return errPathType.ReplaceAllString(errLines.ReplaceAllString(s, ".go"), "*os.PathError")
+}
+
+func TestErrCheck01(tst *testing.T) {
+
+	// Verbose()
+	testTitle("ErrCheck01. passing error checks")
+
+	_, perr := os.Open("/does/not/exist")
+	err := Wrap(ErrCode("not-found", "cannot load: %w", perr), "handler")
+
+	NoError(tst, "nil", nil)
+	ErrorIs(tst, "is", err, os.ErrNotExist)
+	ErrorIs(tst, "is code", err, &Error{Code: "not-found"})
+	ErrorCode(tst, "code", err, "not-found")
+	ErrorContains(tst, "contains", err, "cannot load")
+
+	var pathErr *os.PathError
+	ErrorAs(tst, "as", err, &pathErr)
+	String(tst, "path", pathErr.Path, "/does/not/exist")
+}
+
+func TestErrCheck02(tst *testing.T) {
+
+	// Verbose()
+	testTitle("ErrCheck02. failures show the error chain")
+
+	_, perr := os.Open("/does/not/exist")
+	err := Wrap(ErrCode("not-found", "cannot load: %w", perr), "handler")
+
+	chain := strings.Join([]string{
+		"error chain:",
+		"  1) *check.Error (at t_errcheck_test.go): handler: not-found: cannot load: open /does/not/exist: no such file or directory",
+		"  2) *check.Error [not-found] (at t_errcheck_test.go): not-found: cannot load: open /does/not/exist: no such file or directory",
+		"  3) *os.PathError: open /does/not/exist: no such file or directory",
+		"  4) syscall.Errno: no such file or directory",
+		"",
+	}, "\n")
+	String(tst, "chain", errNormalize(ErrorChain(err)), chain)
+
+	col := NewCollector("errors")
+	NoError(col, "no error", err)
+	ErrorIs(col, "is", err, os.ErrExist)
+	ErrorIs(col, "is nil", nil, os.ErrExist)
+	ErrorCode(col, "code", err, "forbidden")
+	ErrorContains(col, "contains", err, "timeout")
+	var target *Error
+	ErrorAs(col, "as nil", nil, &target)
+	var other *otherError
+	ErrorAs(col, "as", err, &other)
+
+	failures := col.Failures()
+	Int(tst, "number of failures", len(failures), 7)
+	if len(failures) != 7 {
+		return
+	}
+	for i, head := range []string{
+		"unexpected error: handler: not-found: cannot load: open /does/not/exist: no such file or directory\n",
+		"error does not match file already exists\n",
+		"no error; expected error matching file already exists",
+		"no error with code \"forbidden\"\n",
+		"error message does not contain \"timeout\"\n",
+		"no error; expected error of type *check.Error",
+		"no error of type *check.otherError\n",
+	} {
+		res := errNormalize(failures[i][strings.Index(failures[i], ": ")+2:])
+		if i == 2 || i == 5 {
+			String(tst, "failure", res, head)
+			continue
+		}
+		String(tst, "failure", res, head+strings.TrimSuffix(chain, "\n"))
+	}
+}
+
+// otherError is an error type absent from the chains of these tests
+type otherError struct{}
+
+func (*otherError) Error() string { return "num" }
@@ -4,7 +4,14 @@
 
 package check
 
-import "testing"
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
 
 func TestError01(tst *testing.T) {
 
@@ -16,3 +23,59 @@ func TestError01(tst *testing.T) {
 		tst.Errorf("strings should be equal")
 	}
 }
+
+func TestError02(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Error02. Error type with code, caller and cause")
+
+	e := ErrCode("not-found", "user %q not found", "dorival")
+	String(tst, "message", e.Error(), `not-found: user "dorival" not found`)
+
+	var cerr *Error
+	if !errors.As(e, &cerr) {
+		tst.Errorf("errors.As should have found *Error")
+		return
+	}
+	String(tst, "code", cerr.Code, "not-found")
+	String(tst, "file", filepath.Base(cerr.Caller.File), "t_error_test.go")
+	String(tst, "func", cerr.Caller.Func, "github.com/cpmech/lootbag/check.TestError02")
+	if !errors.Is(e, &Error{Code: "not-found"}) {
+		tst.Errorf("errors.Is should match the code")
+	}
+	if errors.Is(e, &Error{Code: "forbidden"}) || errors.Is(e, &Error{}) {
+		tst.Errorf("errors.Is should not match other or empty codes")
+	}
+}
+
+func TestError03(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Error03. wrapping")
+
+	cause := os.ErrNotExist
+	e := Wrap(cause, "cannot open %s", "a.txt")
+	String(tst, "message", e.Error(), "cannot open a.txt: file does not exist")
+	if !errors.Is(e, os.ErrNotExist) {
+		tst.Errorf("errors.Is should find the cause")
+	}
+	if errors.Unwrap(e) != cause {
+		tst.Errorf("Unwrap should return the cause")
+	}
+
+	w := Err("reading config: %w", e)
+	String(tst, "%w", w.Error(), "reading config: cannot open a.txt: file does not exist")
+	if !errors.Is(w, os.ErrNotExist) {
+		tst.Errorf("errors.Is should find the cause through %%w")
+	}
+
+	if Wrap(nil, "nothing") != nil {
+		tst.Errorf("Wrap(nil) should return nil")
+	}
+
+	c := CallerInfo(1)
+	String(tst, "CallerInfo", c.Func, "github.com/cpmech/lootbag/check.TestError03")
+	if !strings.HasSuffix(c.String(), "t_error_test.go:"+strconv.Itoa(c.Line)+" github.com/cpmech/lootbag/check.TestError03") {
+		tst.Errorf("Caller.String is incorrect: %q\n", c.String())
+	}
+}
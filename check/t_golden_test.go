@@ -0,0 +1,66 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGolden01(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Golden01. compare with golden files")
+
+	Golden(tst, "hello", []byte("Hello Golden!  \r\n{\"b\":1,\"a\":[true,null]}\r\n"), UnixNewlines, TrimTrailingSpace)
+	Golden(tst, "data", []byte(`{"b":1.50,"a":[true,null]}`), CanonicalJSON)
+
+	t := new(testing.T)
+	golden(t, "testdata/TestGolden01/hello.golden", []byte("Hello World!\n"), false)
+	if !t.Failed() {
+		tst.Errorf("test should have failed")
+	}
+
+	m := new(testing.T)
+	golden(m, "testdata/TestGolden01/missing.golden", []byte("Hello World!\n"), false)
+	if !m.Failed() {
+		tst.Errorf("test should have failed due to missing golden file")
+	}
+}
+
+func TestGolden02(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Golden02. update golden files")
+
+	dir, err := ioutil.TempDir("", "lootbag-golden")
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "sub", "out.golden")
+	golden(tst, path, []byte("line  \n"), true, TrimTrailingSpace)
+	golden(tst, path, []byte("line\n"), false)
+
+	b, _ := ioutil.ReadFile(path)
+	String(tst, "written", string(b), "line\n")
+}
+
+func TestGolden03(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Golden03. normalizers")
+
+	String(tst, "trailing", string(TrimTrailingSpace([]byte("a \t\nb\n  c  "))), "a\nb\n  c")
+	String(tst, "newlines", string(UnixNewlines([]byte("a\r\nb\rc"))), "a\nb\nc")
+	String(tst, "json", string(CanonicalJSON([]byte(`{"z":1,"a":{"y":2,"b":3}}`))), "{\n  \"a\": {\n    \"b\": 3,\n    \"y\": 2\n  },\n  \"z\": 1\n}\n")
+	String(tst, "not json", string(CanonicalJSON([]byte(`hello`))), "hello")
+	String(tst, "trailing data", string(CanonicalJSON([]byte(`{"a":1} garbage`))), `{"a":1} garbage`)
+	String(tst, "trailing bracket", string(CanonicalJSON([]byte(`{"a":1}]`))), `{"a":1}]`)
+}
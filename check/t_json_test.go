@@ -0,0 +1,75 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJSON01(tst *testing.T) {
+
+	// Verbose()
+	testTitle("JSON01. semantic comparison")
+
+	r := new(testing.T)
+	JSON(r, "equal", `{"success":true,"authorized":true,"data":{"n":1.0,"list":[1,2]}}`, `{
+		"authorized": true,
+		"success": true,
+		"data": {"list": [1e0, 2], "n": 1}
+	}`)
+	if r.Failed() {
+		tst.Errorf("test should not have failed")
+	}
+
+	t := new(testing.T)
+	JSON(t, "different", `{"a":1}`, `{"a":2}`)
+	if !t.Failed() {
+		tst.Errorf("test should have failed")
+	}
+
+	e := new(testing.T)
+	JSON(e, "invalid", `{"a":1`, `{"a":1}`)
+	if !e.Failed() {
+		tst.Errorf("test should have failed due to invalid JSON")
+	}
+}
+
+func TestJSON02(tst *testing.T) {
+
+	// Verbose()
+	testTitle("JSON02. JSONDiff paths")
+
+	got := `{"users":[{"id":"u1","email":"a"},{"id":"u2"}],"n":1,"only got":true,"when":"2019-01-02"}`
+	want := `{"users":[{"id":"<any>","email":"b"},{"id":"u2"},{}],"n":"1","only-want":null,"when":"<regex:^\\d{4}-\\d{2}-\\d{2}$>"}`
+	diffs, err := JSONDiff(got, want)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	String(tst, "diffs", strings.Join(diffs, "\n"), strings.Join([]string{
+		`.n: number 1 != string "1"`,
+		`["only got"]: true != <missing>`,
+		`["only-want"]: <missing> != null`,
+		`.users[0].email: "a" != "b"`,
+		`.users[2]: <missing> != {}`,
+	}, "\n"))
+
+	diffs, _ = JSONDiff(`{"id":123,"when":"yesterday"}`, `{"id":"<regex:^[0-9]+$>","when":"<regex:^\\d+$>"}`)
+	String(tst, "regex", strings.Join(diffs, "\n"), `.when: "yesterday" does not match <regex:^\d+$>`)
+
+	diffs, _ = JSONDiff(`[1,2]`, `[1,2,3]`)
+	String(tst, "root", strings.Join(diffs, "\n"), `[2]: <missing> != 3`)
+
+	for _, got := range []string{`{} {}`, `{"a":1}]`, `{"a":1}}`, `[1] x`} {
+		_, err = JSONDiff(got, `{"a":1}`)
+		if err == nil {
+			tst.Errorf("JSONDiff should have failed due to trailing data in %s", got)
+		}
+	}
+	if _, err = JSONDiff(`{"a":1} `+"\n", `{"a":1}`); err != nil {
+		tst.Errorf("trailing spaces should be accepted: %v", err)
+	}
+}
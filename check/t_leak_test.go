@@ -0,0 +1,65 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func leakyWorker(done chan struct{}) {
+	<-done
+}
+
+func TestLeak01(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Leak01. goroutines that finish")
+
+	NoLeaks(tst)
+
+	done := make(chan struct{})
+	go leakyWorker(done)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(done)
+	}()
+}
+
+func TestLeak02(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Leak02. leaked goroutine")
+
+	done := make(chan struct{})
+	defer close(done)
+
+	col := NewCollector("leak")
+	verify := NoLeaks(col)
+	go leakyWorker(done)
+	start := time.Now()
+	verify()
+	verify() // only the first call checks
+	if time.Since(start) < leakTimeout {
+		tst.Errorf("NoLeaks should have waited for %v\n", leakTimeout)
+	}
+
+	failures := col.Failures()
+	Int(tst, "number of failures", len(failures), 1)
+	if len(failures) == 1 {
+		if !strings.Contains(failures[0], "1 goroutines leaked:") || !strings.Contains(failures[0], "check.leakyWorker") {
+			tst.Errorf("failure should show the stack of the leaked goroutine:\n%s\n", failures[0])
+		}
+	}
+
+	col = NewCollector("ignored")
+	verify = NoLeaks(col, "check.leakyWorker")
+	go leakyWorker(done)
+	verify()
+	if col.Failed() {
+		tst.Errorf("leakyWorker should have been ignored:\n%s", col.Summary())
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatcher01(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Matcher01. matching values")
+
+	var nilMap map[string]int
+	var nilPtr *deepUser
+	body := `{"success":true,"data":{"id":"123"}}`
+
+	That(tst, "equal", []int{1, 2}, Equal([]int{1, 2}))
+	That(tst, "near", 1.0001, Near(1, 1e-3))
+	That(tst, "near int", 3, Near(3.1, 0.2))
+	That(tst, "contains", body, Contains(`"success":true`))
+	That(tst, "contains bytes", []byte(body), Contains("data"))
+	That(tst, "contains error", Err("not found: x"), Contains("not found"))
+	That(tst, "contains element", []string{"a", "b"}, Contains("b"))
+	That(tst, "has prefix", body, HasPrefix("{"))
+	That(tst, "regex", "id-1234", Regex(`^id-\d+$`))
+	That(tst, "len", map[string]int{"a": 1}, Len(1))
+	That(tst, "empty", nilMap, Empty())
+	That(tst, "empty string", "", Empty())
+	That(tst, "nil", nil, Nil())
+	That(tst, "nil pointer", nilPtr, Nil())
+	That(tst, "elements", []string{"b", "a", "b"}, SameElements([]string{"b", "b", "a"}))
+	That(tst, "has key", map[string]int{"a": 1}, HasKey("a"))
+	That(tst, "not", "abc", Not(Contains("x")))
+	That(tst, "all of", body, AllOf(HasPrefix("{"), Contains("123"), Len(len(body))))
+	That(tst, "any of", 404, AnyOf(Equal(200), Equal(404)))
+}
+
+func TestMatcher02(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Matcher02. descriptions and reasons")
+
+	cases := []struct {
+		value   interface{}
+		matcher Matcher
+		desc    string
+		reason  string
+	}{
+		{2, Equal(1), "equal to 1", "2 != 1"},
+		{int64(1), Equal(1), "equal to 1", "type int64 != type int"},
+		{1.5, Near(1, 0.1), "within 0.1 of 1", "difference is 0.5"},
+		{"x", Near(1, 0.1), "within 0.1 of 1", "value of type string is not a number"},
+		{"abc", Contains("x"), `contains "x"`, "substring not found"},
+		{[]int{1}, Contains(2), "contains 2", "element not found among 1 elements"},
+		{3, Contains(2), "contains 2", "value of type int cannot contain elements"},
+		{"abc", HasPrefix("b"), `has prefix "b"`, "prefix not found"},
+		{"abc", Regex(`^b`), `matches regex "^b"`, "regex does not match"},
+		{[]int{1, 2}, Len(1), "has length 1", "has length 2"},
+		{1, Len(1), "has length 1", "value of type int has no length"},
+		{"a", Empty(), "is empty", "has length 1"},
+		{0, Nil(), "is nil", "is not nil"},
+		{[]string{"a", "a", "b"}, SameElements([]string{"a", "c", "c"}), "has elements [a c c] in any order", `missing: "c" (×2); extra: "a", "b"`},
+		{map[string]int{}, HasKey("a"), `has key "a"`, "key not found among 0 keys"},
+		{map[string]int{}, HasKey(1), "has key 1", "key of type int cannot be used with map[string]int"},
+		{"abc", Not(Contains("b")), `not contains "b"`, "it does"},
+		{"abc", AllOf(HasPrefix("a"), Len(1), Contains("x")), `all of (has prefix "a"; has length 1; contains "x")`, `has length 1: has length 3; contains "x": substring not found`},
+		{3, AnyOf(Equal(1), Equal(2)), "any of (equal to 1; equal to 2)", "equal to 1: 3 != 1; equal to 2: 3 != 2"},
+	}
+	for _, c := range cases {
+		String(tst, "description", c.matcher.String(), c.desc)
+		err := c.matcher.Match(c.value)
+		if err == nil {
+			tst.Errorf("%s should have failed for %v\n", c.desc, c.value)
+			continue
+		}
+		String(tst, "reason", err.Error(), c.reason)
+	}
+
+	col := NewCollector("that")
+	That(col, "body", `{"success":false}`, Contains(`"success":true`))
+	failures := col.Failures()
+	Int(tst, "number of failures", len(failures), 1)
+	if len(failures) == 1 {
+		res := failures[0][strings.Index(failures[0], ": ")+2:]
+		String(tst, "failure", res, "value: \"{\\\"success\\\":false}\"\nexpected: contains \"\\\"success\\\":true\"\nbut: substring not found")
+	}
+
+	Panics(tst, "invalid regex", func() { Regex("(") })
+}
@@ -0,0 +1,121 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNumeric01(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Numeric01. Tolerance modes")
+
+	Float64(tst, "abs", 1e-15, AbsTol(0.1).Distance(1, 1.5), 0.5)
+	Float64(tst, "rel", 1e-15, RelTol(0.1).Distance(100, 101), 1.0/101)
+	Float64(tst, "rel zero", 1e-15, RelTol(0.1).Distance(0, 0), 0)
+	Float64(tst, "ulp", 1e-15, UlpTol(1).Distance(1, math.Nextafter(1, 2)), 1)
+	Float64(tst, "ulp across zero", 1e-15, UlpTol(1).Distance(math.Nextafter(0, -1), math.Nextafter(0, 1)), 2)
+	Float64(tst, "ulp -0 +0", 1e-15, UlpTol(1).Distance(math.Copysign(0, -1), 0), 0)
+
+	if !RelTol(1e-3).Within(1000, 1000.5) {
+		tst.Errorf("1000 and 1000.5 should be within relative tolerance 1e-3")
+	}
+	if UlpTol(4).Within(1, 1+1e-10) {
+		tst.Errorf("1 and 1+1e-10 should not be within 4 ULPs")
+	}
+
+	t := new(testing.T)
+	Float64Tol(t, "1 != 1.1", RelTol(1e-3), 1, 1.1)
+	if !t.Failed() {
+		tst.Errorf("test should have failed")
+	}
+
+	r := new(testing.T)
+	Float64Tol(r, "1 == next(1)", UlpTol(1), 1, math.Nextafter(1, 2))
+	if r.Failed() {
+		tst.Errorf("test should not have failed")
+	}
+}
+
+func TestNumeric02(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Numeric02. Float64s")
+
+	r := new(testing.T)
+	Float64s(r, "equal", 1e-10, []float64{1, 2, 3}, []float64{1, 2, 3 + 1e-12})
+	if r.Failed() {
+		tst.Errorf("(r) test should not have failed")
+	}
+
+	t := new(testing.T)
+	Float64s(t, "different", 1e-10, []float64{1, 2, 3}, []float64{1, 2.5, 3.1})
+	if !t.Failed() {
+		tst.Errorf("(t) test should have failed")
+	}
+
+	a := new(testing.T)
+	Float64s(a, "lengths", 1e-10, []float64{1, 2, 3}, nil)
+	if !a.Failed() {
+		tst.Errorf("(a) test should have failed")
+	}
+
+	b := new(testing.T)
+	Float64sTol(b, "NaN", RelTol(1e-10), []float64{1, math.NaN()}, []float64{1, 2})
+	if !b.Failed() {
+		tst.Errorf("(b) test should have failed")
+	}
+
+	c := new(testing.T)
+	Float64sTol(c, "ULP", UlpTol(2), []float64{1, 2}, []float64{math.Nextafter(1, 2), 2})
+	if c.Failed() {
+		tst.Errorf("(c) test should not have failed")
+	}
+
+	cmp := &floatCmp{tol: AbsTol(1e-10)}
+	cmp.compare("[0]", 1, 1)
+	cmp.compare("[1]", 2, 2.5)
+	cmp.compare("[2]", 3, 4)
+	String(tst, "failed", cmp.failed(), "a[1]=2 != b[1]=2.5\n(2 mismatches; max error = 1 at [2]; absolute tolerance 1e-10)\n")
+	String(tst, "stop", cmp.compare("[3]", math.Inf(-1), 1), "a[3]=-Inf is Inf\n")
+}
+
+func TestNumeric03(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Numeric03. Deep2 and Complex128s")
+
+	r := new(testing.T)
+	Deep2(r, "equal", 1e-10, [][]float64{{1, 2}, {3, 4}}, [][]float64{{1, 2}, {3, 4}})
+	if r.Failed() {
+		tst.Errorf("(r) test should not have failed")
+	}
+
+	t := new(testing.T)
+	Deep2(t, "different", 1e-10, [][]float64{{1, 2}, {3, 4}}, [][]float64{{1, 2}, {3, 5}})
+	if !t.Failed() {
+		tst.Errorf("(t) test should have failed")
+	}
+
+	a := new(testing.T)
+	Deep2Tol(a, "row lengths", RelTol(1e-10), [][]float64{{1, 2}, {3, 4}}, [][]float64{{1, 2}, {3}})
+	if !a.Failed() {
+		tst.Errorf("(a) test should have failed")
+	}
+
+	s := new(testing.T)
+	Complex128s(s, "equal", 1e-10, []complex128{1 + 2i, 3 - 4i}, []complex128{1 + 2i, 3 - 4i})
+	if s.Failed() {
+		tst.Errorf("(s) test should not have failed")
+	}
+
+	u := new(testing.T)
+	Complex128s(u, "different imag", 1e-10, []complex128{1 + 2i, 3 - 4i}, []complex128{1 + 2i, 3 + 4i})
+	if !u.Failed() {
+		tst.Errorf("(u) test should have failed")
+	}
+}
@@ -42,3 +42,39 @@ func TestPanic04(tst *testing.T) {
 	defer RecoverTst(tst)
 	MaybePanic(false, "hello world: %v", "123")
 }
+
+func TestPanic05(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Panic05. Panics and PanicsWith")
+
+	value := Panics(tst, "Panic panics", func() { Panic("hello world: %v", "123") })
+	String(tst, "value", value.(error).Error(), "hello world: 123")
+
+	PanicsWith(tst, "substring", "world: 1", func() { Panic("hello world: %v", "123") })
+	PanicsWith(tst, "regex", `^hello \w+: \d+$`, func() { Panic("hello world: %v", "123") })
+	PanicsWith(tst, "not a string", "code 7", func() { panic(Err("code %d", 7)) })
+
+	t := new(testing.T)
+	Panics(t, "does not panic", func() {})
+	if !t.Failed() {
+		tst.Errorf("(t) test should have failed")
+	}
+
+	r := new(testing.T)
+	PanicsWith(r, "does not match", "goodbye", func() { Panic("hello world") })
+	if !r.Failed() {
+		tst.Errorf("(r) test should have failed")
+	}
+
+	s := new(testing.T)
+	PanicsWith(s, "invalid regex", "(", func() { Panic("hello world") })
+	if !s.Failed() {
+		tst.Errorf("(s) test should have failed")
+	}
+
+	_, panicked := capturePanic(func() { panic(nil) })
+	if !panicked {
+		tst.Errorf("panic(nil) should have been captured")
+	}
+}
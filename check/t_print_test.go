@@ -0,0 +1,93 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"context"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestPrint01(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Print01. global verbosity")
+
+	defer atomic.StoreInt32(&globalLevel, atomic.LoadInt32(&globalLevel))
+	defer atomic.StoreInt32(&defaultLevel, atomic.LoadInt32(&defaultLevel))
+	defer os.Setenv("LOOTBAG_VERBOSE", os.Getenv("LOOTBAG_VERBOSE"))
+
+	atomic.StoreInt32(&globalLevel, levelUnset)
+	atomic.StoreInt32(&defaultLevel, levelUnset)
+	os.Setenv("LOOTBAG_VERBOSE", "debug")
+	Int(tst, "from env", int(Verbosity()), int(LevelDebug))
+	os.Setenv("LOOTBAG_VERBOSE", "quiet")
+	Int(tst, "env is read once", int(Verbosity()), int(LevelDebug))
+
+	os.Setenv("LOOTBAG_VERBOSE", "")
+	SetVerbosity(LevelQuiet)
+	Int(tst, "set", int(Verbosity()), int(LevelQuiet))
+	stdout, _, _ := CaptureOutput(func() { testTitle("hidden") })
+	String(tst, "quiet title", stdout, "")
+
+	Verbose()
+	Int(tst, "compatibility", int(Verbosity()), int(LevelVerbose))
+
+	for _, s := range []string{"0", "quiet", "1", "normal", "false", "2", "verbose", "true", "3", "DEBUG"} {
+		if _, ok := ParseLevel(s); !ok {
+			tst.Errorf("ParseLevel(%q) failed\n", s)
+		}
+	}
+	if _, ok := ParseLevel("loud"); ok {
+		tst.Errorf("ParseLevel(\"loud\") should have failed\n")
+	}
+}
+
+func TestPrint02(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Print02. test and context verbosity")
+
+	SetTestVerbosity(tst, LevelQuiet)
+	Int(tst, "test", int(TestVerbosity(tst)), int(LevelQuiet))
+
+	tst.Run("sub", func(t *testing.T) {
+		Int(t, "inherited", int(TestVerbosity(t)), int(LevelQuiet))
+		SetTestVerbosity(t, LevelDebug)
+		Int(t, "subtest", int(TestVerbosity(t)), int(LevelDebug))
+		stdout, _, _ := CaptureOutput(func() { Int(t, "1 == 1", 1, 1) })
+		String(t, "verbose subtest", stdout, "1 == 1: OK\n")
+	})
+	if _, ok := testLevels.Load(tst.Name() + "/sub"); ok {
+		tst.Errorf("level of subtest should have been removed\n")
+	}
+	stdout, _, _ := CaptureOutput(func() { Int(tst, "1 == 1", 1, 1) })
+	String(tst, "quiet test", stdout, "")
+
+	ctx := WithVerbosity(context.Background(), LevelDebug)
+	Int(tst, "context", int(ContextVerbosity(ctx)), int(LevelDebug))
+	Int(tst, "no context", int(ContextVerbosity(context.Background())), int(Verbosity()))
+}
+
+func TestPrint03(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Print03. concurrent access")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			col := NewCollector("parallel" + string(rune('a'+i)))
+			SetTestVerbosity(col, Level(i%4))
+			Int(tst, "level", int(TestVerbosity(col)), i%4)
+			testLevels.Delete(col.Name())
+		}(i)
+	}
+	wg.Wait()
+}
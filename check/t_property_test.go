@@ -0,0 +1,118 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+type propertyPoint struct {
+	X, Y  int
+	Label string
+}
+
+func TestProperty01(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Property01. properties that hold")
+
+	Property(tst, func(a, b int) bool {
+		return a+b == b+a
+	}, GenInt(-1000, 1000), GenInt(-1000, 1000))
+
+	Property(tst, func(s string) error {
+		if !utf8.ValidString(s) {
+			return Err("invalid string %q", s)
+		}
+		return nil
+	}, GenString("", 20))
+
+	Property(tst, func(x []float64, m map[string]bool, flag bool) bool {
+		for _, v := range x {
+			if v < -1 || v > 1 {
+				return false
+			}
+		}
+		return len(m) <= 5
+	}, GenSlice(GenFloat64(-1, 1), 10), GenMap(GenString("abc", 3), GenBool(), 5), GenBool())
+
+	Property(tst, func(p propertyPoint) bool {
+		return p.X >= 0 && p.X <= 10 && p.Label == "origin"
+	}, GenStruct(propertyPoint{Label: "origin"}, map[string]Generator{"X": GenInt(0, 10), "Y": GenInt(-5, 5)}))
+}
+
+func TestProperty02(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Property02. shrinking")
+
+	col := NewCollector("property")
+	col.Run(func(r Reporter) {
+		PropertyWith(r, PropertyConfig{Runs: 100, Seed: 123, MaxShrinks: 1000}, func(n int) bool {
+			return n < 100
+		}, GenInt(-1000, 1000))
+	})
+	failures := col.Failures()
+	Int(tst, "number of failures", len(failures), 1)
+	if !strings.Contains(failures[0], "(seed 123; rerun with LOOTBAG_SEED=123)") {
+		tst.Errorf("failure should report the seed:\n%s", failures[0])
+	}
+	if !strings.Contains(failures[0], "\ncounterexample: 100\n") {
+		tst.Errorf("counterexample should have been shrunk to 100:\n%s", failures[0])
+	}
+
+	col = NewCollector("property")
+	col.Run(func(r Reporter) {
+		PropertyWith(r, PropertyConfig{Runs: 100, Seed: 7, MaxShrinks: 1000}, func(s []string) bool {
+			for _, v := range s {
+				if strings.Contains(v, "b") {
+					panic("found b")
+				}
+			}
+			return true
+		}, GenSlice(GenString("ab", 10), 10))
+	})
+	failures = col.Failures()
+	Int(tst, "number of failures", len(failures), 1)
+	if !strings.Contains(failures[0], "\ncounterexample: []string{\"b\"}\n") || !strings.HasSuffix(failures[0], "reason: panic: found b") {
+		tst.Errorf("counterexample should have been shrunk to [b]:\n%s", failures[0])
+	}
+}
+
+func TestProperty03(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Property03. reproducible and invalid properties")
+
+	g := GenSlice(GenInt(0, 100), 20)
+	a := g.Generate(rand.New(rand.NewSource(42)))
+	b := g.Generate(rand.New(rand.NewSource(42)))
+	Deep(tst, "same seed", a, b)
+
+	t := new(testing.T)
+	Property(t, func(s string) bool { return true }, GenInt(0, 1))
+	if !t.Failed() {
+		tst.Errorf("(t) test should have failed due to wrong argument type")
+	}
+
+	r := new(testing.T)
+	Property(r, func(n int) int { return n }, GenInt(0, 1))
+	if !r.Failed() {
+		tst.Errorf("(r) test should have failed due to wrong return type")
+	}
+
+	PanicsWith(tst, "GenInt", "must not be greater", func() { GenInt(1, 0) })
+	PanicsWith(tst, "GenString", "maxLen=-1 must not be negative", func() { GenString("", -1) })
+	PanicsWith(tst, "GenSlice", "maxLen=-1 must not be negative", func() { GenSlice(GenBool(), -1) })
+	PanicsWith(tst, "GenMap", "maxLen=-1 must not be negative", func() { GenMap(GenBool(), GenBool(), -1) })
+	PanicsWith(tst, "GenStruct", "no exported field", func() { GenStruct(propertyPoint{}, map[string]Generator{"Z": GenInt(0, 1)}) })
+
+	Deep(tst, "shrink int", GenInt(-10, 10).Shrink(8), []interface{}{0, 4, 7})
+	Deep(tst, "shrink positive range", GenInt(5, 10).Shrink(8), []interface{}{5, 6, 7})
+	Deep(tst, "shrink string", GenString("ab", 5).Shrink("ba"), []interface{}{"", "b", "a", "a", "b", "aa"})
+}
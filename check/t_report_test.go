@@ -0,0 +1,143 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	Main(m)
+}
+
+// saveRecorder saves the state of the recorder and returns a function restoring it
+func saveRecorder() (restore func()) {
+	enabled := atomic.LoadInt32(&recorder.enabled)
+	recorder.mu.Lock()
+	assertions := recorder.assertions
+	last := make(map[string]time.Time)
+	for k, v := range recorder.last {
+		last[k] = v
+	}
+	recorder.mu.Unlock()
+	return func() {
+		recorder.mu.Lock()
+		recorder.assertions, recorder.last = assertions, last
+		recorder.mu.Unlock()
+		atomic.StoreInt32(&recorder.enabled, enabled)
+	}
+}
+
+// reportOf returns the recorded assertions of a test
+func reportOf(name string) (list []Assertion) {
+	for _, a := range Assertions() {
+		if a.Test == name {
+			list = append(list, a)
+		}
+	}
+	return
+}
+
+func TestReport01(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Report01. recording assertions")
+
+	defer saveRecorder()()
+	ResetAssertions()
+	RecordAssertions()
+
+	col := NewCollector("report01")
+	Int(col, "answer", 42, 42)
+	String(col, "greeting", "hello", "world")
+	Deep(col, "deep", []int{1}, []int{2})
+	Pass(col, "custom")
+	Fail(col, "custom", "custom failure\n")
+
+	list := reportOf("report01")
+	Int(tst, "number of assertions", len(list), 5)
+	if len(list) != 5 {
+		return
+	}
+	String(tst, "msg", list[0].Msg, "answer")
+	Bools(tst, "passed", []bool{list[0].Passed, list[1].Passed, list[2].Passed}, []bool{true, false, false})
+	String(tst, "failure", list[1].Failure, "\n\"hello\"\nIS NOT EQUAL TO\n\"world\"")
+	String(tst, "deep failure", list[2].Failure, "[0]: 1 != 2")
+	Bools(tst, "custom passed", []bool{list[3].Passed, list[4].Passed}, []bool{true, false})
+	String(tst, "custom failure", list[4].Failure, "custom failure")
+	Int(tst, "custom failures collected", len(col.Failures()), 3)
+	String(tst, "caller", filepath.Base(list[0].Caller.File), "t_report_test.go")
+	Int64(tst, "first duration", int64(list[0].Duration), 0)
+	if list[2].Duration <= 0 {
+		tst.Errorf("duration should be measured from the previous assertion\n")
+	}
+}
+
+func TestReport02(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Report02. JUnit and JSON reports")
+
+	defer saveRecorder()()
+	ResetAssertions()
+	RecordAssertions()
+
+	col := NewCollector("report02")
+	Int(col, "answer", 42, 42)
+	Int(col, "a < b", 1, 2)
+
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, "suite"); err != nil {
+		tst.Errorf("WriteJUnit failed: %v\n", err)
+		return
+	}
+	xml := buf.String()
+	for _, s := range []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<testsuite name="suite" tests="`,
+		`<testcase name="answer" classname="report02" time="0.000000" file="`,
+		`<testcase name="a &lt; b" classname="report02"`,
+		`<failure message="1 != 2">1 != 2</failure>`,
+	} {
+		if !strings.Contains(xml, s) {
+			tst.Errorf("JUnit report should contain %q:\n%s\n", s, xml)
+		}
+	}
+
+	dir := TempTree(tst, nil)
+	fn := filepath.Join(dir, "reports", "report.json")
+	if err := WriteReport(fn); err != nil {
+		tst.Errorf("WriteReport failed: %v\n", err)
+		return
+	}
+	var list []Assertion
+	if err := json.Unmarshal([]byte(readString(tst, fn)), &list); err != nil {
+		tst.Errorf("invalid JSON report: %v\n", err)
+		return
+	}
+	var found []string
+	for _, a := range list {
+		if a.Test == "report02" {
+			found = append(found, a.Msg)
+		}
+	}
+	String(tst, "messages", strings.Join(found, ","), "answer,a < b")
+}
+
+// readString reads a file
+func readString(tst *testing.T, fn string) string {
+	b, err := ioutil.ReadFile(fn)
+	if err != nil {
+		tst.Errorf("cannot read file: %v\n", err)
+	}
+	return string(b)
+}
@@ -0,0 +1,72 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"strings"
+	"testing"
+)
+
+// testing.TB must satisfy Reporter
+var _ Reporter = testing.TB(nil)
+
+func TestReporter01(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Reporter01. Collector")
+
+	col := NewCollector("smoke")
+	String(tst, "name", col.Name(), "smoke")
+
+	ok := col.Run(func(r Reporter) {
+		Int(r, "1 == 1", 1, 1)
+		String(r, "hello == hello", "hello", "hello")
+	})
+	if !ok || col.Failed() {
+		tst.Errorf("collector should not have failed")
+		return
+	}
+	String(tst, "pass", col.Summary(), "--- PASS: smoke\n")
+
+	reached := false
+	ok = col.Run(func(r Reporter) {
+		Int(r, "1 != 2", 1, 2)
+		r.FailNow()
+		reached = true
+	})
+	if ok || !col.Failed() {
+		tst.Errorf("collector should have failed")
+		return
+	}
+	if reached {
+		tst.Errorf("FailNow should have stopped the function")
+	}
+
+	failures := col.Failures()
+	Int(tst, "number of failures", len(failures), 1)
+	if !strings.HasPrefix(failures[0], "t_reporter_test.go:") || !strings.HasSuffix(failures[0], ": 1 != 2") {
+		tst.Errorf("failure message is incorrect: %q\n", failures[0])
+	}
+	if !strings.HasPrefix(col.Summary(), "--- FAIL: smoke (1 failures)\n    t_reporter_test.go:") {
+		tst.Errorf("summary is incorrect:\n%s", col.Summary())
+	}
+}
+
+func TestReporter02(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Reporter02. Collector propagates other panics")
+
+	defer RecoverTstPanicIsOK(tst)
+	NewCollector("panic").Run(func(r Reporter) {
+		panic("not a failure")
+	})
+}
+
+func BenchmarkReporter01(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Int(b, "i == i", i, i)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// softLines removes the "file:line" positions from a summary
+var softLines = regexp.MustCompile(`t_soft_test\.go:\d+`)
+
+func TestSoft01(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Soft01. summary of failures")
+
+	col := NewCollector("soft")
+	ok := col.Run(func(r Reporter) {
+		soft := NewSoft(r)
+		Int(soft, "status", 404, 200)
+		String(soft, "content type", "application/json", "application/json")
+		Deep(soft, "data", map[string]int{"a": 1, "b": 2}, map[string]int{"a": 0, "b": 3})
+		soft.Errorf("direct failure\n")
+		func() {
+			defer RecoverTst(soft)
+			Panic("boom")
+		}()
+		Int(soft, "reached", 1, 1)
+		Bools(tst, "failed", []bool{soft.Failed()}, []bool{true})
+		String(tst, "name", soft.Name(), "soft")
+	})
+	if ok {
+		tst.Errorf("collection should have failed\n")
+		return
+	}
+
+	failures := col.Failures()
+	Int(tst, "number of failures", len(failures), 1)
+	if len(failures) != 1 {
+		return
+	}
+	res := softLines.ReplaceAllString(failures[0], "POS")
+	res = res[strings.Index(res, ": ")+2:]
+	res = res[:strings.Index(res, "  4) ")]
+	correct := strings.Join([]string{
+		"4 soft assertions failed:",
+		"  1) POS: status",
+		"     404 != 200",
+		"  2) POS: data",
+		`     ["a"]: 1 != 0`,
+		`     ["b"]: 2 != 3`,
+		"  3) POS",
+		"     direct failure",
+		"",
+	}, "\n")
+	String(tst, "summary", res, correct)
+	if !strings.Contains(failures[0], "  4) ") || !strings.Contains(failures[0], ": recover\n     boom") {
+		tst.Errorf("summary should include the panic:\n%s\n", failures[0])
+	}
+}
+
+func TestSoft02(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Soft02. cap and explicit Report")
+
+	col := NewCollector("soft")
+	soft := NewSoft(col)
+	soft.MaxShown = 2
+	for i := 0; i < 5; i++ {
+		Int(soft, "i", i, -1)
+	}
+	if col.Failed() {
+		tst.Errorf("failures should only be reported by Report\n")
+	}
+	soft.Report()
+	soft.Report() // nothing new
+
+	failures := col.Failures()
+	Int(tst, "number of failures", len(failures), 1)
+	if len(failures) == 1 {
+		res := softLines.ReplaceAllString(failures[0], "POS")
+		res = res[strings.Index(res, ": ")+2:]
+		correct := strings.Join([]string{
+			"5 soft assertions failed:",
+			"  1) POS: i",
+			"     0 != -1",
+			"  2) POS: i",
+			"     1 != -1",
+			"  ... and 3 more",
+		}, "\n")
+		String(tst, "summary", res, correct)
+	}
+
+	col = NewCollector("fail now")
+	soft = NewSoft(col)
+	soft.FailNow()
+	soft.Report()
+	Int(tst, "FailNow", len(col.Failures()), 1)
+}
@@ -0,0 +1,73 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func stackHelper() []Caller {
+	return CaptureStack(0)
+}
+
+func TestStack01(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Stack01. CaptureStack and FormatStack")
+
+	stack := stackHelper()
+	String(tst, "first", stack[0].Func, "github.com/cpmech/lootbag/check.stackHelper")
+	String(tst, "second", stack[1].Func, "github.com/cpmech/lootbag/check.TestStack01")
+
+	lines := strings.Split(strings.TrimSpace(FormatStack(stack)), "\n")
+	Int(tst, "number of lines (testing and runtime are hidden)", len(lines), 2)
+	if !strings.HasSuffix(lines[0], " check.stackHelper") || !strings.Contains(lines[0], "t_stack_test.go:") {
+		tst.Errorf("first line is incorrect: %q\n", lines[0])
+	}
+
+	String(tst, "short", shortFuncName("github.com/cpmech/lootbag/lio.Atoi"), "lio.Atoi")
+	String(tst, "method", shortFuncName("net/http.(*Server).Serve"), "http.(*Server).Serve")
+	String(tst, "main", shortFuncName("main.main"), "main.main")
+}
+
+func TestStack02(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Stack02. Panic attaches stack")
+
+	value := Panics(tst, "Panic", func() { MaybePanic(true, "hello %s", "world") })
+	e, ok := value.(*Error)
+	if !ok {
+		tst.Errorf("panic value should be *Error\n")
+		return
+	}
+	String(tst, "message", e.Error(), "hello world")
+	String(tst, "caller file", filepath.Base(e.Caller.File), "t_stack_test.go")
+	if !strings.Contains(FormatStack(e.Stack), "check.TestStack02") {
+		tst.Errorf("stack should contain the test function:\n%s", FormatStack(e.Stack))
+	}
+	if strings.Contains(FormatStack(e.Stack), "check.MaybePanic") {
+		tst.Errorf("stack should not contain functions of the check package:\n%s", FormatStack(e.Stack))
+	}
+}
+
+func TestStack03(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Stack03. RecoverTst reports stack")
+
+	col := NewCollector("recover")
+	col.Run(func(r Reporter) {
+		defer RecoverTst(r)
+		panic("plain panic")
+	})
+	failures := col.Failures()
+	Int(tst, "number of failures", len(failures), 1)
+	if !strings.Contains(failures[0], "plain panic") || !strings.Contains(failures[0], "t_stack_test.go:") {
+		tst.Errorf("failure should contain message and position of panic:\n%s", failures[0])
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+type tableCase struct {
+	Name  string
+	A, B  string
+	Want  string
+	Skip  bool
+	Focus bool
+}
+
+func TestTable01(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Table01. named cases")
+
+	var count int32
+	Table(tst, []tableCase{
+		{Name: "hello", A: "hello", B: " world", Want: "hello world"},
+		{Name: "empty", A: "", B: "", Want: ""},
+		{Name: "skipped", A: "a", B: "b", Want: "wrong", Skip: true},
+	}, func(t *testing.T, c tableCase) {
+		atomic.AddInt32(&count, 1)
+		String(t, c.Name, c.A+c.B, c.Want)
+	})
+	Int(tst, "number of cases run", int(count), 2)
+}
+
+func TestTable02(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Table02. focus and parallel")
+
+	var names []string
+	col := NewCollector("focus")
+	runTable(tst, []tableCase{
+		{Name: "a"},
+		{Name: "b", Focus: true},
+		{Name: "c", Focus: true},
+	}, func(t *testing.T, c tableCase) {
+		names = append(names, c.Name)
+	}, false, col)
+	String(tst, "focused cases", strings.Join(names, ","), "b,c")
+	Int(tst, "focus failures", len(col.Failures()), 1)
+	That(tst, "focus failure", strings.Join(col.Failures(), "\n"), Contains("2 of 3 cases have Focus set; the other cases are skipped\n(remove Focus before committing)"))
+
+	var count int32
+	TableParallel(tst, []tableCase{{Name: "x"}, {Name: "y"}, {Name: "z"}}, func(t *testing.T, c tableCase) {
+		atomic.AddInt32(&count, 1)
+	})
+	tst.Cleanup(func() {
+		Int(tst, "parallel cases", int(atomic.LoadInt32(&count)), 3)
+	})
+}
+
+func TestTable03(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Table03. invalid input")
+
+	t := new(testing.T)
+	runTable(t, []int{1, 2}, func(t *testing.T, c int) {}, false, t)
+	if !t.Failed() {
+		tst.Errorf("test should have failed due to invalid cases")
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTree01(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Tree01. TempTree and Tree")
+
+	spec := map[string]string{
+		"go.mod":           "module example\n",
+		"bin/run.sh 0755":  "echo hello\n",
+		"data/empty/":      "",
+		"data/secret 0600": "s3cr3t",
+	}
+	dir := TempTree(tst, spec)
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "bin", "run.sh"))
+	if err != nil {
+		tst.Errorf("cannot read file: %v\n", err)
+		return
+	}
+	String(tst, "content", string(b), "echo hello\n")
+	Tree(tst, dir, spec)
+
+	// modes are only checked when given; parent directories are implied
+	Tree(tst, dir, map[string]string{
+		"go.mod":      "module example\n",
+		"bin/run.sh":  "echo hello\n",
+		"data/empty/": "",
+		"data/secret": "s3cr3t",
+	})
+}
+
+func TestTree02(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Tree02. differences")
+
+	dir := TempTree(tst, map[string]string{
+		"a.txt":         "one\ntwo\n",
+		"b.txt":         "b",
+		"c.sh 0644":     "",
+		"d/":            "",
+		"extra/x/y.txt": "y",
+		"extra.txt":     "",
+	})
+
+	col := NewCollector("tree")
+	Tree(col, dir, map[string]string{
+		"a.txt":     "one\nTWO\n",
+		"b.txt":     "B",
+		"c.sh 0755": "",
+		"d":         "",
+		"missing/":  "",
+	})
+	failures := col.Failures()
+	Int(tst, "number of failures", len(failures), 1)
+	if len(failures) != 1 {
+		return
+	}
+	correct := strings.Join([]string{
+		"~ a.txt: content differs",
+		"--- a",
+		"+++ b",
+		"@@ -1,3 +1,3 @@",
+		" one",
+		"-two",
+		"+TWO",
+		"?^",
+		" ",
+		`~ b.txt: "b" != "B"`,
+		"~ c.sh: mode 0644 != 0755",
+		"~ d: directory != file",
+		"- missing/",
+		"+ extra/",
+		"+ extra.txt",
+	}, "\n")
+	res := failures[0][strings.Index(failures[0], "differs:\n")+len("differs:\n"):]
+	String(tst, "differences", res, correct)
+}
+
+func TestTree03(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Tree03. txtar and cleanup")
+
+	spec := ParseTxtar(`comment
+-- go.mod --
+module example
+-- bin/run.sh 0755 --
+echo hello
+-- empty/ --
+`)
+	Int(tst, "number of entries", len(spec), 3)
+	String(tst, "go.mod", spec["go.mod"], "module example\n")
+	String(tst, "run.sh", spec["bin/run.sh 0755"], "echo hello\n")
+	String(tst, "empty", spec["empty/"], "")
+
+	var dir string
+	col := NewCollector("cleanup")
+	col.Run(func(r Reporter) {
+		dir = TempTree(r, spec)
+		Tree(r, dir, spec)
+	})
+	if col.Failed() {
+		tst.Errorf("%s", col.Summary())
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		tst.Errorf("temporary directory should have been removed\n")
+	}
+}
+
+func TestTree04(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Tree04. paths outside the temporary directory")
+
+	for _, key := range []string{"../escaped.txt", "a/../../escaped.txt", "/abs.txt", "../"} {
+		col := NewCollector("outside")
+		col.Run(func(r Reporter) {
+			TempTree(r, map[string]string{key: "x"})
+		})
+		failures := col.Failures()
+		Int(tst, key+": number of failures", len(failures), 1)
+		That(tst, key+": failure", strings.Join(failures, "\n"), Contains("path is not inside the temporary directory"))
+		if _, err := os.Stat(filepath.Join(os.TempDir(), "escaped.txt")); !os.IsNotExist(err) {
+			tst.Errorf("%s: file should not have been created outside the temporary directory\n", key)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWait01(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Wait01. WaitFor")
+
+	count := 0
+	attempts, err := WaitFor(time.Second, time.Millisecond, func() error {
+		count++
+		if count < 4 {
+			return Err("not yet: %d", count)
+		}
+		return nil
+	})
+	if err != nil {
+		tst.Errorf("WaitFor should have succeeded: %v\n", err)
+		return
+	}
+	Int(tst, "attempts", attempts, 4)
+
+	start := time.Now()
+	attempts, err = WaitFor(50*time.Millisecond, time.Millisecond, func() error {
+		return Err("never ready")
+	})
+	elapsed := time.Since(start)
+	if err == nil || err.Error() != "never ready" {
+		tst.Errorf("WaitFor should have returned the last error; got %v\n", err)
+	}
+	if elapsed < 50*time.Millisecond || elapsed > time.Second {
+		tst.Errorf("WaitFor should have waited for about 50ms; waited %v\n", elapsed)
+	}
+	if attempts < 3 || attempts > 10 {
+		tst.Errorf("polling should be exponential: %d attempts\n", attempts)
+	}
+}
+
+func TestWait02(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Wait02. Eventually")
+
+	var ready int32
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		atomic.StoreInt32(&ready, 1)
+	}()
+	Eventually(tst, "goroutine sets ready", time.Second, time.Millisecond, func() error {
+		if atomic.LoadInt32(&ready) == 0 {
+			return Err("not ready")
+		}
+		return nil
+	})
+
+	col := NewCollector("eventually")
+	col.Run(func(r Reporter) {
+		Eventually(r, "never ready", 20*time.Millisecond, time.Millisecond, func() error {
+			return Err("server is down")
+		})
+	})
+	failures := col.Failures()
+	Int(tst, "number of failures", len(failures), 1)
+	if !strings.Contains(failures[0], "condition not satisfied after ") || !strings.HasSuffix(failures[0], "last error: server is down") {
+		tst.Errorf("failure message is incorrect:\n%s", failures[0])
+	}
+}
+
+func TestWait03(tst *testing.T) {
+
+	// Verbose()
+	testTitle("Wait03. Never")
+
+	Never(tst, "never ready", 20*time.Millisecond, time.Millisecond, func() error {
+		return Err("not ready")
+	})
+
+	count := 0
+	t := new(testing.T)
+	Never(t, "ready at third attempt", time.Second, time.Millisecond, func() error {
+		count++
+		if count < 3 {
+			return Err("not ready")
+		}
+		return nil
+	})
+	if !t.Failed() {
+		tst.Errorf("test should have failed")
+	}
+	Int(tst, "stops at first success", count, 3)
+}
@@ -0,0 +1,140 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// Table runs each case as a subtest: body(t, case)
+//
+//   cases -- slice of structs with a "Name string" field; optional "Skip bool" and "Focus bool"
+//            fields mark cases to be skipped or to be run exclusively
+//   body -- function such as func(t *testing.T, c myCase)
+//
+//   Example:
+//     check.Table(tst, []struct {
+//         Name  string
+//         In    string
+//         Out   int
+//         Focus bool
+//     }{
+//         {Name: "zero", In: "0", Out: 0},
+//         {Name: "one", In: "1", Out: 1},
+//     }, func(t *testing.T, c struct{ ... }) {
+//         check.Int(t, c.Name, lio.Atoi(c.In), c.Out)
+//     })
+//
+//   NOTE: (1) a summary is printed after all cases finish
+//         (2) the test fails if any case has Focus set, so that focused cases are not committed
+//             by mistake; the focused cases still run to help debugging
+//
+func Table(tst *testing.T, cases, body interface{}) {
+	tst.Helper()
+	runTable(tst, cases, body, false, tst)
+}
+
+// TableParallel runs each case as a parallel subtest; see Table
+func TableParallel(tst *testing.T, cases, body interface{}) {
+	tst.Helper()
+	runTable(tst, cases, body, true, tst)
+}
+
+// tableSummary counts the results of cases
+type tableSummary struct {
+	mu                      sync.Mutex
+	passed, failed, skipped int
+}
+
+// runTable implements Table and TableParallel
+//  focusRep -- receives the failure caused by focused cases; normally tst
+func runTable(tst *testing.T, cases, body interface{}, parallel bool, focusRep Reporter) {
+	tst.Helper()
+
+	// check input
+	c := reflect.ValueOf(cases)
+	if c.Kind() != reflect.Slice || c.Type().Elem().Kind() != reflect.Struct {
+		fail(tst, "table", "cases must be a slice of structs; got %T\n", cases)
+		return
+	}
+	caseType := c.Type().Elem()
+	if f, ok := caseType.FieldByName("Name"); !ok || f.Type.Kind() != reflect.String {
+		fail(tst, "table", "cases must have a \"Name string\" field\n")
+		return
+	}
+	f := reflect.ValueOf(body)
+	testingType := reflect.TypeOf(tst)
+	if f.Kind() != reflect.Func || f.Type().NumIn() != 2 || f.Type().In(0) != testingType || f.Type().In(1) != caseType {
+		fail(tst, "table", "body must be a func(*testing.T, %v); got %T\n", caseType, body)
+		return
+	}
+
+	// focus
+	focused := 0
+	for i := 0; i < c.Len(); i++ {
+		if tableFlag(c.Index(i), "Focus") {
+			focused++
+		}
+	}
+	focus := focused > 0
+	if focus {
+		fail(focusRep, "table focus", "%d of %d cases have Focus set; the other cases are skipped\n(remove Focus before committing)\n", focused, c.Len())
+	}
+
+	// summary
+	sum := new(tableSummary)
+	summary := func() {
+		if TestVerbosity(tst) < LevelNormal {
+			return
+		}
+		mode := ""
+		if focus {
+			mode = " (focus)"
+		}
+		fmt.Printf("   . . . table . . .   %s%s: %d passed, %d failed, %d skipped\n", tst.Name(), mode, sum.passed, sum.failed, sum.skipped)
+	}
+	if parallel {
+		tst.Cleanup(summary) // parallel subtests finish after the caller returns
+	} else {
+		defer summary()
+	}
+
+	// run
+	for i := 0; i < c.Len(); i++ {
+		item := c.Index(i)
+		name := item.FieldByName("Name").String()
+		skip := tableFlag(item, "Skip") || (focus && !tableFlag(item, "Focus"))
+		tst.Run(name, func(t *testing.T) {
+			defer func() {
+				sum.mu.Lock()
+				defer sum.mu.Unlock()
+				switch {
+				case t.Skipped():
+					sum.skipped++
+				case t.Failed():
+					sum.failed++
+				default:
+					sum.passed++
+				}
+			}()
+			if skip {
+				t.SkipNow()
+			}
+			if parallel {
+				t.Parallel()
+			}
+			f.Call([]reflect.Value{reflect.ValueOf(t), item})
+		})
+	}
+}
+
+// tableFlag returns the value of a bool field of a case; or false if there is no such field
+func tableFlag(item reflect.Value, name string) bool {
+	f := item.FieldByName(name)
+	return f.IsValid() && f.Kind() == reflect.Bool && f.Bool()
+}
@@ -0,0 +1,274 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// default modes of entries created by TempTree
+const (
+	treeFileMode os.FileMode = 0644
+	treeDirMode  os.FileMode = 0755
+)
+
+// treeModeSuffix matches the optional mode at the end of keys of tree specifications; e.g. "run.sh 0755"
+var treeModeSuffix = regexp.MustCompile(` (0[0-7]{3})$`)
+
+// txtarMarker matches the lines starting a file in txtar-style descriptions; e.g. "-- a/b.txt --"
+var txtarMarker = regexp.MustCompile(`^-- (.+) --$`)
+
+// ParseTxtar converts a txtar-style description of a directory tree into the map used by
+// TempTree and Tree. Each file starts with a "-- name --" line and its content follows;
+// text before the first marker is ignored
+//
+//   Example:
+//     -- go.mod --
+//     module example
+//     -- bin/run.sh 0755 --
+//     echo hello
+//     -- empty/ --
+//
+func ParseTxtar(text string) (spec map[string]string) {
+	spec = make(map[string]string)
+	name := ""
+	var content []string
+	flush := func() {
+		if name != "" {
+			spec[name] = strings.Join(content, "")
+		}
+	}
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if m := txtarMarker.FindStringSubmatch(strings.TrimRight(line, "\r\n")); m != nil {
+			flush()
+			name, content = m[1], nil
+			continue
+		}
+		content = append(content, line)
+	}
+	flush()
+	return
+}
+
+// TempTree creates a directory tree inside a new temporary directory and returns its path
+//
+//   spec maps slash-separated paths to the content of files:
+//     "a/b.txt"      -- file with mode 0644; parent directories are created with mode 0755
+//     "bin/run 0755" -- file with mode 0755
+//     "empty/"       -- directory (the content is ignored); "empty/ 0700" sets its mode
+//
+//  NOTE: the directory is removed when the test finishes if tst has a Cleanup method
+//        (e.g. testing.TB or Collector); see also ParseTxtar
+func TempTree(tst Reporter, spec map[string]string) (dir string) {
+	tst.Helper()
+	prefix := "lootbag-" + strings.Replace(tst.Name(), "/", "_", -1) + "-"
+	dir, err := ioutil.TempDir("", prefix)
+	if err != nil {
+		fail(tst, "temporary tree", "cannot create temporary directory: %v\n", err)
+		tst.FailNow()
+		return
+	}
+	if c, ok := tst.(interface{ Cleanup(func()) }); ok {
+		c.Cleanup(func() { os.RemoveAll(dir) })
+	}
+	keys := make([]string, 0, len(spec))
+	for key := range spec {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		e := parseTreeKey(key)
+		if err = e.create(dir, spec[key]); err != nil {
+			fail(tst, "temporary tree", "cannot create %q: %v\n", key, err)
+			tst.FailNow()
+			return
+		}
+	}
+	passf(tst, "temporary tree", "temporary tree %s: OK\n", dir)
+	return
+}
+
+// Tree checks whether the directory tree at dir has exactly the entries given by want
+//
+//   want has the same format as the spec of TempTree; however, modes are only checked
+//   when given and parent directories of listed entries need not be listed
+//
+//   The failure message lists the differences, one per line:
+//     "- path" -- missing entry
+//     "+ path" -- unexpected entry (entries inside unexpected directories are not shown)
+//     "~ path" -- entry with a different type, content or mode
+//
+func Tree(tst Reporter, dir string, want map[string]string) {
+	tst.Helper()
+	got, err := readTree(dir)
+	if err != nil {
+		fail(tst, "tree "+dir, "cannot read tree: %v\n", err)
+		return
+	}
+	if diffs := treeDiff(got, want); len(diffs) > 0 {
+		fail(tst, "tree "+dir, "tree at %q differs:\n%s\n", dir, strings.Join(diffs, "\n"))
+		return
+	}
+	printOK(tst, "tree "+dir)
+}
+
+// treeEntry holds a file or directory of a tree
+type treeEntry struct {
+	name    string      // slash-separated path without the trailing "/" of directories
+	isDir   bool        // entry is a directory
+	mode    os.FileMode // permission bits
+	hasMode bool        // mode was given in the specification
+	content string      // content of file
+}
+
+// parseTreeKey converts a key of a tree specification to treeEntry
+func parseTreeKey(key string) (e treeEntry) {
+	if m := treeModeSuffix.FindStringSubmatch(key); m != nil {
+		mode, _ := strconv.ParseUint(m[1], 8, 32)
+		e.mode, e.hasMode = os.FileMode(mode), true
+		key = key[:len(key)-len(m[0])]
+	}
+	e.isDir = strings.HasSuffix(key, "/")
+	e.name = path.Clean(strings.TrimSuffix(key, "/"))
+	if !e.hasMode {
+		e.mode = treeFileMode
+		if e.isDir {
+			e.mode = treeDirMode
+		}
+	}
+	return
+}
+
+// kind returns "directory" or "file"
+func (o treeEntry) kind() string {
+	if o.isDir {
+		return "directory"
+	}
+	return "file"
+}
+
+// create creates the entry inside dir
+//  NOTE: (1) Chmod is called after creation in order to bypass the umask
+//        (2) absolute paths and paths outside dir (e.g. "../x") are rejected
+func (o treeEntry) create(dir, content string) (err error) {
+	fn := filepath.Join(dir, filepath.FromSlash(o.name))
+	rel, err := filepath.Rel(dir, fn)
+	if path.IsAbs(o.name) || filepath.IsAbs(filepath.FromSlash(o.name)) || err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return Err("path is not inside the temporary directory")
+	}
+	if o.isDir {
+		if err = os.MkdirAll(fn, o.mode); err != nil {
+			return
+		}
+		return os.Chmod(fn, o.mode)
+	}
+	if err = os.MkdirAll(filepath.Dir(fn), treeDirMode); err != nil {
+		return
+	}
+	if err = ioutil.WriteFile(fn, []byte(content), o.mode); err != nil {
+		return
+	}
+	return os.Chmod(fn, o.mode)
+}
+
+// readTree returns all entries inside dir
+func readTree(dir string) (entries map[string]treeEntry, err error) {
+	entries = make(map[string]treeEntry)
+	err = filepath.Walk(dir, func(fn string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, fn)
+		if err != nil || rel == "." {
+			return err
+		}
+		e := treeEntry{name: filepath.ToSlash(rel), isDir: info.IsDir(), mode: info.Mode().Perm()}
+		if !e.isDir {
+			b, err := ioutil.ReadFile(fn)
+			if err != nil {
+				return err
+			}
+			e.content = string(b)
+		}
+		entries[e.name] = e
+		return nil
+	})
+	return
+}
+
+// treeDiff returns the differences between the entries of a tree and a specification
+func treeDiff(got map[string]treeEntry, want map[string]string) (diffs []string) {
+
+	// wanted entries and their parent directories
+	wanted := make(map[string]treeEntry)
+	implied := make(map[string]bool)
+	for key, content := range want {
+		e := parseTreeKey(key)
+		e.content = content
+		wanted[e.name] = e
+		for p := path.Dir(e.name); p != "."; p = path.Dir(p) {
+			implied[p] = true
+		}
+	}
+
+	// missing and different entries
+	for _, name := range sortedTreeNames(wanted) {
+		w := wanted[name]
+		g, ok := got[name]
+		switch {
+		case !ok:
+			diffs = append(diffs, "- "+treeLabel(w))
+		case g.isDir != w.isDir:
+			diffs = append(diffs, fmt.Sprintf("~ %s: %s != %s", name, g.kind(), w.kind()))
+		case w.hasMode && g.mode != w.mode:
+			diffs = append(diffs, fmt.Sprintf("~ %s: mode %04o != %04o", name, g.mode, w.mode))
+		case !w.isDir && g.content != w.content:
+			if strings.Contains(g.content, "\n") || strings.Contains(w.content, "\n") {
+				diffs = append(diffs, fmt.Sprintf("~ %s: content differs\n%s", name, strings.TrimSuffix(Diff(g.content, w.content), "\n")))
+			} else {
+				diffs = append(diffs, fmt.Sprintf("~ %s: %q != %q", name, g.content, w.content))
+			}
+		}
+	}
+
+	// unexpected entries
+	unexpected := make(map[string]bool)
+	for _, name := range sortedTreeNames(got) {
+		if _, ok := wanted[name]; ok || implied[name] {
+			continue
+		}
+		unexpected[name] = true
+		if unexpected[path.Dir(name)] {
+			continue
+		}
+		diffs = append(diffs, "+ "+treeLabel(got[name]))
+	}
+	return
+}
+
+// treeLabel returns the name of an entry with a trailing "/" for directories
+func treeLabel(e treeEntry) string {
+	if e.isDir {
+		return e.name + "/"
+	}
+	return e.name
+}
+
+// sortedTreeNames returns the sorted names of entries
+func sortedTreeNames(entries map[string]treeEntry) (names []string) {
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
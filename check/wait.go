@@ -0,0 +1,83 @@
+// Copyright 2019 The LootBag Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package check
+
+import (
+	"time"
+)
+
+// minPollInterval is the smallest interval between calls to the condition of WaitFor
+const minPollInterval = time.Millisecond
+
+// maxPollFactor limits the growth of the polling interval: interval ≤ maxPollFactor × initial interval
+const maxPollFactor = 16
+
+// WaitFor calls cond until it returns nil or timeout is reached
+//
+//   Input:
+//     timeout -- maximum waiting time
+//     interval -- initial interval between calls; it doubles after each attempt [minimum 1ms]
+//     cond -- returns nil when the condition is satisfied; or an error explaining why not
+//
+//   Output:
+//     attempts -- number of calls to cond
+//     err -- the last error returned by cond; nil if the condition was satisfied
+//
+func WaitFor(timeout, interval time.Duration, cond func() error) (attempts int, err error) {
+	return WaitForClock(RealClock, timeout, interval, cond)
+}
+
+// WaitForClock is the same as WaitFor but measures time and sleeps with clock
+//  NOTE: with a FakeClock, the whole timeout elapses instantly
+func WaitForClock(clock Clock, timeout, interval time.Duration, cond func() error) (attempts int, err error) {
+	deadline := clock.Now().Add(timeout)
+	if interval < minPollInterval {
+		interval = minPollInterval
+	}
+	delay := interval
+	for {
+		attempts++
+		if err = cond(); err == nil {
+			return
+		}
+		left := deadline.Sub(clock.Now())
+		if left <= 0 {
+			return
+		}
+		if delay > left {
+			delay = left
+		}
+		clock.Sleep(delay)
+		if delay < maxPollFactor*interval {
+			delay *= 2
+		}
+	}
+}
+
+// Eventually checks whether cond returns nil within timeout
+//  NOTE: cond is polled with exponentially growing intervals starting at interval; see WaitFor
+func Eventually(tst Reporter, msg string, timeout, interval time.Duration, cond func() error) {
+	tst.Helper()
+	start := time.Now()
+	attempts, err := WaitFor(timeout, interval, cond)
+	if err != nil {
+		fail(tst, msg, "condition not satisfied after %d attempts in %v\nlast error: %v\n", attempts, time.Since(start).Round(time.Millisecond), err)
+		return
+	}
+	passf(tst, msg, "%s: OK (%d attempts)\n", msg, attempts)
+}
+
+// Never checks whether cond never returns nil during timeout
+//  NOTE: cond is polled with exponentially growing intervals starting at interval; see WaitFor
+func Never(tst Reporter, msg string, timeout, interval time.Duration, cond func() error) {
+	tst.Helper()
+	start := time.Now()
+	attempts, err := WaitFor(timeout, interval, cond)
+	if err == nil {
+		fail(tst, msg, "condition satisfied at attempt %d after %v\n", attempts, time.Since(start).Round(time.Millisecond))
+		return
+	}
+	passf(tst, msg, "%s: OK (%d attempts; last error: %v)\n", msg, attempts, err)
+}
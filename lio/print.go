@@ -11,24 +11,21 @@ import (
 	"github.com/cpmech/lootbag/check"
 )
 
-// verboseMode controls message level
-var verboseMode = false
-
 // Verbose is an auxiliary function to set verbose mode
-// NOTE: also sets check.Verbose() accordingly
+// NOTE: the level is shared with the check package; see check.SetVerbosity
+//       (levels set by check.SetTestVerbosity do not affect the functions in this file)
 func Verbose() {
-	verboseMode = true
 	check.Verbose()
 }
 
 // IsVerbose returns verbose mode status
 func IsVerbose() bool {
-	return verboseMode
+	return check.Verbosity() >= check.LevelVerbose
 }
 
 // Pl prints a new line
 func Pl() {
-	if !verboseMode {
+	if !IsVerbose() {
 		return
 	}
 	fmt.Printf("\n")
@@ -36,7 +33,7 @@ func Pl() {
 
 // Pf prints formatted string
 func Pf(msg string, prm ...interface{}) {
-	if !verboseMode {
+	if !IsVerbose() {
 		return
 	}
 	fmt.Printf(msg, prm...)
@@ -57,9 +54,10 @@ func Ff(w io.Writer, msg string, prm ...interface{}) {
 
 // TestTitle prints title of test
 func TestTitle(title string) {
-	if verboseMode {
+	switch l := check.Verbosity(); {
+	case l >= check.LevelVerbose:
 		fmt.Printf("\n=== %s =================\n", title)
-		return
+	case l >= check.LevelNormal:
+		fmt.Printf("   . . . testing . . .   %s\n", title)
 	}
-	fmt.Printf("   . . . testing . . .   %s\n", title)
 }
@@ -22,10 +22,15 @@ func TestWriteFile01(tst *testing.T) {
 	Ff(b0, "Hello World!\n")
 	Ff(b1, "(using lootbag.lio.WriteFile)\n")
 
-	dir := "/tmp"
+	dir := check.TempTree(tst, map[string]string{"existing.txt": "old"})
 	fn := "t_fileio_test_WriteFile01.txt"
-	WriteFile(dir, fn, false, b0.Bytes(), b1.Bytes())
+	WriteFile(filepath.Join(dir, "out"), fn, false, b0.Bytes(), b1.Bytes())
 
-	res := ReadFile(filepath.Join(dir, fn))
+	res := ReadFile(filepath.Join(dir, "out", fn))
 	check.String(tst, "content of file", string(res), "Hello World!\n(using lootbag.lio.WriteFile)\n")
+
+	check.Tree(tst, dir, map[string]string{
+		"existing.txt": "old",
+		"out/" + fn:    "Hello World!\n(using lootbag.lio.WriteFile)\n",
+	})
 }
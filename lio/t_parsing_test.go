@@ -5,6 +5,8 @@
 package lio
 
 import (
+	"math"
+	"strconv"
 	"testing"
 
 	"github.com/cpmech/lootbag/check"
@@ -76,3 +78,31 @@ func TestParsing04(tst *testing.T) {
 	defer check.RecoverTstPanicIsOK(tst)
 	Atof("dorival")
 }
+
+func TestParsing05(tst *testing.T) {
+
+	//Verbose()
+	TestTitle("Parsing05. panic messages")
+
+	check.PanicsWith(tst, "Atob", "cannot parse string representing Bool: dorival", func() { Atob("dorival") })
+	check.PanicsWith(tst, "Atoi", "cannot parse string representing int: 1.5", func() { Atoi("1.5") })
+	check.PanicsWith(tst, "Atof", "cannot parse string representing float64: 1,5", func() { Atof("1,5") })
+}
+
+func TestParsing06(tst *testing.T) {
+
+	//Verbose()
+	TestTitle("Parsing06. round trips")
+
+	check.Property(tst, func(n int) bool {
+		return Atoi(strconv.Itoa(n)) == n
+	}, check.GenInt(math.MinInt32, math.MaxInt32))
+
+	check.Property(tst, func(x float64) bool {
+		return Atof(strconv.FormatFloat(x, 'g', -1, 64)) == x
+	}, check.GenFloat64(-1e10, 1e10))
+
+	check.Property(tst, func(flag bool) bool {
+		return Atob(Btoa(flag)) == flag && Itob(Btoi(flag)) == flag
+	}, check.GenBool())
+}
@@ -6,6 +6,8 @@ package lio
 
 import (
 	"testing"
+
+	"github.com/cpmech/lootbag/check"
 )
 
 func TestSf01(tst *testing.T) {
@@ -18,3 +20,25 @@ func TestSf01(tst *testing.T) {
 		tst.Errorf("res = %q. want = \"123\"\n", res)
 	}
 }
+
+func TestPf01(tst *testing.T) {
+
+	//Verbose()
+	TestTitle("Pf01. print only in verbose mode")
+
+	defer check.SetVerbosity(check.Verbosity())
+
+	check.SetVerbosity(check.LevelNormal)
+	stdout, _, _ := check.CaptureOutput(func() {
+		Pf("hello %d", 123)
+		Pl()
+	})
+	check.String(tst, "quiet", stdout, "")
+
+	check.SetVerbosity(check.LevelVerbose)
+	stdout, _, _ = check.CaptureOutput(func() {
+		Pf("hello %d", 123)
+		Pl()
+	})
+	check.String(tst, "verbose", stdout, "hello 123\n")
+}
@@ -6,28 +6,30 @@ package neto
 
 import (
 	"net/http"
-	"testing"
 
 	"github.com/cpmech/lootbag/check"
 )
 
 // CheckGET checks if GET request works
 // Returns response that may be <nil> in case of failure
-func CheckGET(tst *testing.T, url string) (response *http.Response) {
+func CheckGET(tst check.Reporter, url string) (response *http.Response) {
+	tst.Helper()
 	response, err := http.Get(url)
 	if err != nil {
-		tst.Errorf("GET failed: %v\n", err)
+		check.Fail(tst, "GET", "GET failed: %v\n", err)
 		return
 	}
+	check.Pass(tst, "GET")
 	return
 }
 
 // CheckResponse checks whether response is equal to correctBody or not
 // NOTE: (1) reponse may be nil
 //       (2) the response.Body data will be deleted/consumed by io.Copy()
-func CheckResponse(tst *testing.T, response *http.Response, correctBody string) {
+func CheckResponse(tst check.Reporter, response *http.Response, correctBody string) {
+	tst.Helper()
 	if response == nil {
-		tst.Errorf("cannot extract response.Body because response is <nil>\n")
+		check.Fail(tst, "response", "cannot extract response.Body because response is <nil>\n")
 		return
 	}
 	bodyText := ExtractResponseBodyText(response)
@@ -13,6 +13,8 @@ import (
 	"strings"
 	"syscall"
 	"time"
+
+	"github.com/cpmech/lootbag/check"
 )
 
 // DatastoreEmulator spawn datastore emulator
@@ -79,18 +81,19 @@ func DatastoreEmulator(projectID, port string) (stop func()) {
 
 	// reset database
 	log.Printf("############ resetting datastore\n")
-	retry, numberOfRetries := 0, 10
-	for retry = 1; retry <= numberOfRetries; retry++ {
-		time.Sleep(1000 * time.Millisecond)
+	attempts, err := check.WaitFor(10*time.Second, 250*time.Millisecond, func() error {
 		response, err := http.Post("http://localhost:"+port+"/reset", "application/json", nil)
-		if response != nil && err == nil {
-			if response.StatusCode == http.StatusOK {
-				break
-			}
+		if err != nil {
+			return err
 		}
-	}
-	if retry >= numberOfRetries {
-		log.Printf("############ cannot reset datastore emulator\n")
+		response.Body.Close()
+		if response.StatusCode != http.StatusOK {
+			return check.Err("unexpected http status code: %d", response.StatusCode)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("############ cannot reset datastore emulator after %d attempts: %v\n", attempts, err)
 		stop()
 		os.Exit(1)
 	}
@@ -5,6 +5,7 @@
 package neto
 
 import (
+	"errors"
 	"io/ioutil"
 	"net/http"
 
@@ -41,6 +42,12 @@ func Jhandler(hfcn JSONhandler, parser JSONparser) http.HandlerFunc {
 		// catch errors
 		defer func() {
 			if err := recover(); err != nil {
+				if e, ok := err.(error); ok {
+					var cerr *check.Error
+					if errors.As(e, &cerr) {
+						lio.Pf("Jhandler: %v\n    at %v\n", cerr, cerr.Caller)
+					}
+				}
 				lio.Ff(w, RjsonFailed(err))
 			}
 		}()
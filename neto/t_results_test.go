@@ -56,6 +56,11 @@ func TestResults02(tst *testing.T) {
 		tst.Errorf("success should be true\n")
 		return
 	}
+	var keys []string
+	for key := range res.Data {
+		keys = append(keys, key)
+	}
+	check.ElementsMatch(tst, "keys", keys, []string{"memberA", "memberB", "memberC", "memberD"})
 	if iface, ok := res.Data["memberA"]; ok {
 		check.String(tst, "memberA", iface.(string), "A")
 	} else {
@@ -132,3 +137,22 @@ func TestResults03(tst *testing.T) {
 		return
 	}
 }
+
+func TestResults04(tst *testing.T) {
+
+	// lio.Verbose()
+	lio.TestTitle("Results04. ToJSON with data")
+
+	res := NewResults()
+	res.Authorized = true
+	res.Set("memberA", "A")
+	res.Set("memberB", 123)
+	res.Set("token", "abc-123")
+	check.JSON(tst, "ToJSON", res.ToJSON(), `{
+		"authorized": true,
+		"success": false,
+		"memberA": "A",
+		"memberB": 123.0,
+		"token": "<regex:^[a-z]+-[0-9]+$>"
+	}`)
+}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"testing"
 
 	"github.com/cpmech/lootbag/check"
@@ -20,6 +21,9 @@ func TestSendRequest01(tst *testing.T) {
 	// lio.Verbose()
 	lio.TestTitle("SendRequest01. GET")
 
+	// the server and client must not leave goroutines behind
+	check.NoLeaks(tst)
+
 	// create test server
 	server := httptest.NewServer(Ehandler(func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte("hello tester"))
@@ -112,12 +116,15 @@ func TestSendForm01(tst *testing.T) {
 		},
 	}
 
+	// output directory
+	dir := check.TempTree(tst, nil)
+
 	// create test server
 	parseForm, multipart, doPanic := true, true, true
 	server := httptest.NewServer(restrict.Handler(Ehandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		owner := FormGetParam("owner", r, parseForm, multipart, doPanic)
 		title := FormGetParam("title", r, parseForm, multipart, doPanic)
-		path := FormGetAndSaveFile("/tmp/neto/files", "image", r, parseForm, doPanic)
+		path := FormGetAndSaveFile(filepath.Join(dir, "files"), "image", r, parseForm, doPanic)
 		WjsonAllGoodWithData(w, "owner", owner, "title", title, "path", path)
 	}))))
 	defer server.Close()
@@ -130,8 +137,11 @@ func TestSendForm01(tst *testing.T) {
 	)
 
 	// check
-	correctOutput := `{"authorized":true,"success":true,"data":{"owner":"tester@testing.co","title":"Just Testing","path":"/tmp/neto/files/doc.png"}}`
+	correctOutput := `{"authorized":true,"success":true,"data":{"owner":"tester@testing.co","title":"Just Testing","path":"` + dir + `/files/doc.png"}}`
 	check.String(tst, "response", string(responseBody), correctOutput)
+	check.Tree(tst, dir, map[string]string{
+		"files/doc.png": string(lio.ReadFile("./samples/doc.png")),
+	})
 }
 
 func TestSendForm02(tst *testing.T) {